@@ -0,0 +1,123 @@
+package censorbib
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nickng/bibtex"
+)
+
+// entryAdded returns the date in the entry's added field, which says when
+// the paper was added to CensorBib, e.g.: added = {2024-03-14}.  The zero time
+// means that the entry has no added field.
+func entryAdded(entry *bibtex.BibEntry) (time.Time, error) {
+	added, ok := entry.Fields["added"]
+	if !ok {
+		return time.Time{}, nil
+	}
+	date := strings.TrimSpace(toStr(added))
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q; expected YYYY-MM-DD", date)
+	}
+	return t, nil
+}
+
+// formatAdded returns the given date added as RFC 3339 timestamp, or the empty
+// string if we don't know it.
+func formatAdded(added time.Time) string {
+	if added.IsZero() {
+		return ""
+	}
+	return added.Format(time.RFC3339)
+}
+
+// LoadAddedFromGit sets the date added of the entries that lack an added
+// field to the date of the commit that added them to the given .bib file.  It
+// runs git, so the file must be part of a git repository whose history is
+// complete.  Entries that were never committed keep the zero time.
+func (b *Bibliography) LoadAddedFromGit(path string) error {
+	added, err := gitAdded(path)
+	if err != nil {
+		return err
+	}
+	for i := range b.entries {
+		entry := &b.entries[i]
+		if entry.Added.IsZero() {
+			entry.Added = added[entry.CiteName]
+		}
+	}
+	return nil
+}
+
+// gitAdded returns the date of the commit that first added each cite name to
+// the given .bib file, according to git.
+func gitAdded(path string) (map[string]time.Time, error) {
+	dir, file := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	shallow, err := git(dir, "rev-parse", "--is-shallow-repository")
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(string(shallow)) == "true" {
+		return nil, errors.New("git history is incomplete; fetch all of it using: git fetch --unshallow")
+	}
+	// Each commit starts with a NUL byte and the commit date, followed by its
+	// added and removed lines, oldest commit first.
+	log, err := git(dir, "log", "--reverse", "--format=%x00%cI", "--patch", "--unified=0", "--", file)
+	if err != nil {
+		return nil, err
+	}
+	return parseGitLog(log)
+}
+
+// parseGitLog returns the date of the first commit in the given output of
+// git log that adds a line with each cite name.
+func parseGitLog(log []byte) (map[string]time.Time, error) {
+	added := make(map[string]time.Time)
+	var date time.Time
+	scanner := bufio.NewScanner(bytes.NewReader(log))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if commit, ok := strings.CutPrefix(line, "\x00"); ok {
+			var err error
+			if date, err = time.Parse(time.RFC3339, commit); err != nil {
+				return nil, fmt.Errorf("unexpected output of git log: %w", err)
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, "+") {
+			continue
+		}
+		citeName, ok := extractCiteName(line[1:])
+		if !ok {
+			continue
+		}
+		if _, ok := added[citeName]; !ok {
+			added[citeName] = date
+		}
+	}
+	return added, scanner.Err()
+}
+
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("failed to run git: %w", err)
+	}
+	return out, nil
+}
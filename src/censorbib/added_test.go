@@ -0,0 +1,99 @@
+package censorbib
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseGitLog(t *testing.T) {
+	log := "\x002024-01-02T10:00:00+01:00\n" +
+		"\n" +
+		"diff --git a/references.bib b/references.bib\n" +
+		"+++ b/references.bib\n" +
+		"@@ -0,0 +1,3 @@\n" +
+		"+@misc{Doe2024a,\n" +
+		"+\ttitle = {Paper},\n" +
+		"+}\n" +
+		"\x002024-06-01T00:00:00Z\n" +
+		"\n" +
+		"@@ -1 +1,2 @@\n" +
+		"-@misc{Doe2024a,\n" +
+		"+@article{Doe2024a,\n" +
+		"+@inproceedings{Müller2024a,\n"
+
+	got, err := parseGitLog([]byte(log))
+	if err != nil {
+		t.Fatalf("failed to parse git log: %v", err)
+	}
+	want := map[string]string{
+		"Doe2024a":    "2024-01-02T10:00:00+01:00",
+		"Müller2024a": "2024-06-01T00:00:00Z",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d cite names but got %v", len(want), got)
+	}
+	for citeName, date := range want {
+		if got := formatAdded(got[citeName]); got != date {
+			t.Errorf("%s: expected %s but got %s", citeName, date, got)
+		}
+	}
+}
+
+// commitFile writes the given contents to the file and commits it, using
+// the given date as commit date.  The file's directory becomes a git
+// repository if it isn't one already.
+func commitFile(t *testing.T, path, contents, date string) {
+	t.Helper()
+	dir := filepath.Dir(path)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", filepath.Base(path)},
+		{"-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "commit", "-q", "-m", "Add paper"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, out)
+		}
+	}
+}
+
+func TestLoadAddedFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	path := filepath.Join(t.TempDir(), "references.bib")
+	entry := func(citeName string) string {
+		return "@misc{" + citeName + ",\n\tauthor = {Jane Doe},\n\ttitle = {Paper},\n\tyear = {2024},\n\turl = {https://example.com/paper.pdf},\n}\n"
+	}
+	commitFile(t, path, entry("Doe2024a"), "2024-01-02T00:00:00Z")
+	commitFile(t, path, entry("Doe2024a")+"\n"+entry("Doe2024b"), "2024-06-01T00:00:00Z")
+	contents := entry("Doe2024a") + "\n" + entry("Doe2024b") + "\n" + entry("Doe2024c")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	bib, err := Load(strings.NewReader(contents))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+	if err := bib.LoadAddedFromGit(path); err != nil {
+		t.Fatalf("failed to load dates added: %v", err)
+	}
+	for i, want := range []time.Time{
+		time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		{}, // Not committed yet.
+	} {
+		if got := bib.Entries()[i].Added; !got.Equal(want) {
+			t.Errorf("%s: expected %v but got %v", bib.Entries()[i].CiteName, want, got)
+		}
+	}
+}
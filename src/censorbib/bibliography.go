@@ -0,0 +1,282 @@
+// Package censorbib parses, checks, formats, and renders the BibTeX file that
+// powers CensorBib.
+package censorbib
+
+import (
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nickng/bibtex"
+)
+
+// Matches e.g.: @inproceedings{Müller2024a,
+var re = regexp.MustCompile(`(?i)^@[a-z]+\s*\{\s*([^,\s]+)\s*,`)
+
+// Entry augments bibtex.BibEntry with the entry's raw record in the .bib file.
+type Entry struct {
+	bibtex.BibEntry
+	RawBibtex string
+	// When the paper was added to CensorBib, or the zero time if we don't
+	// know.
+	Added time.Time
+}
+
+// Bibliography is a parsed and checked .bib file.
+type Bibliography struct {
+	entries []Entry
+	// Parent entries, like @proceedings, by cite name.  Other entries can
+	// inherit their fields using crossref, but they aren't papers.
+	parents map[string]Entry
+	// The .bib file that we loaded the bibliography from.
+	source []byte
+	// Whether the HTML page embeds COinS spans.
+	coins bool
+	// When the bibliography was last updated, as the header shows it.  The
+	// zero time means now.
+	updated time.Time
+	// Entries added in this many days before the last update are marked as
+	// new.
+	newDays int
+}
+
+// By default, entries added in the last month are marked as new.
+const defaultNewDays = 30
+
+// Load parses and checks the BibTeX read from r.
+func Load(r io.Reader) (*Bibliography, error) {
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return load("", contents)
+}
+
+// LoadFile is like Load, but reads the given .bib file, and mentions its path
+// in errors.
+func LoadFile(path string) (*Bibliography, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return load(path, contents)
+}
+
+func load(path string, contents []byte) (*Bibliography, error) {
+	entries, err := parseBib(path, contents)
+	if err != nil {
+		return nil, err
+	}
+	bib := &Bibliography{parents: make(map[string]Entry), source: contents, newDays: defaultNewDays}
+	for _, entry := range entries {
+		if isParent(&entry.BibEntry) {
+			bib.parents[entry.CiteName] = entry
+		} else {
+			bib.entries = append(bib.entries, entry)
+		}
+	}
+	return bib, nil
+}
+
+// Entries returns the bibliography's entries, in the order in which they
+// appear in the .bib file, unless Sort was called.
+func (b *Bibliography) Entries() []Entry {
+	return b.entries
+}
+
+// Sort sorts the bibliography's entries by year (descending), venue, title,
+// first author (last name first), and cite name, which is the order in which
+// we display them.
+func (b *Bibliography) Sort() {
+	sortEntries(b.entries)
+}
+
+// ExpandMacros replaces the string macros in the entries' raw BibTeX with
+// their values, so that each entry's raw BibTeX stands on its own, e.g. when
+// copied from the BibTeX modal.  By default, the raw BibTeX is exactly as it
+// appears in the .bib file.
+func (b *Bibliography) ExpandMacros() {
+	for i := range b.entries {
+		entry := &b.entries[i]
+		entry.RawBibtex = expandRawMacros(entry.RawBibtex, &entry.BibEntry)
+	}
+}
+
+// InlineCrossrefs copies the fields that entries inherit from their parents
+// into the entries' raw BibTeX, and removes their crossref field, so that
+// each entry's raw BibTeX stands on its own, e.g. when copied from the BibTeX
+// modal.  Call it before ExpandMacros, if at all.
+func (b *Bibliography) InlineCrossrefs() {
+	for i := range b.entries {
+		entry := &b.entries[i]
+		target, ok := crossrefTarget(&entry.BibEntry)
+		if !ok {
+			continue
+		}
+		entry.RawBibtex = inlineRawCrossref(entry.RawBibtex, b.parents[target].RawBibtex)
+	}
+}
+
+// EmbedCOinS makes the HTML page embed a COinS span in every entry, so that
+// reference managers like Zotero can import the papers from the page.
+func (b *Bibliography) EmbedCOinS() {
+	b.coins = true
+}
+
+// SetUpdated sets the date of the last update that the HTML page shows, which
+// is the day on which it was built by default.  See SourceDate.
+func (b *Bibliography) SetUpdated(t time.Time) {
+	b.updated = t
+}
+
+// SetNewDays sets how many days before the last update an entry must have
+// been added to be marked as new.  Zero marks no entry as new.
+func (b *Bibliography) SetNewDays(days int) {
+	b.newDays = days
+}
+
+func (b *Bibliography) entryOptions() entryOptions {
+	opts := entryOptions{coins: b.coins}
+	if b.newDays > 0 {
+		updated := b.updated
+		if updated.IsZero() {
+			updated = time.Now()
+		}
+		opts.newSince = updated.AddDate(0, 0, -b.newDays)
+	}
+	return opts
+}
+
+func toStr(b bibtex.BibString) string {
+	if b == nil {
+		return ""
+	}
+	return b.String()
+}
+
+func parseBib(path string, contents []byte) ([]Entry, error) {
+	idx := newLineIndex(path, contents)
+	// Parse the blocks one by one.  That tells us which one is broken, and
+	// lets us make sure that all string macros are defined, which the
+	// vendored parser doesn't handle gracefully.
+	scope := newMacroScope()
+	parsed := []*bibtex.BibEntry{}
+	for _, raw := range scanRawBibEntries(contents) {
+		bib, err := scope.parse(raw.raw)
+		if err != nil {
+			citeName, _ := extractCiteName(raw.raw)
+			return nil, idx.parseError(raw, citeName, err)
+		}
+		parsed = append(parsed, bib.Entries...)
+	}
+
+	rawByCiteName, err := extractRawBibEntries(path, contents)
+	if err != nil {
+		return nil, err
+	}
+	bibEntries := []Entry{}
+	errs := []*Error{}
+	for _, v := range resolveCrossrefs(parsed) {
+		raw := rawByCiteName[v.entry.CiteName]
+		errs = append(errs, idx.errorf(fieldOffset(raw, "crossref"), v.entry.CiteName, "crossref", "%s", v.msg))
+	}
+	for _, entry := range parsed {
+		raw, ok := rawByCiteName[entry.CiteName]
+		if !ok {
+			errs = append(errs, &Error{
+				Path:     path,
+				CiteName: entry.CiteName,
+				Err:      errors.New("could not find raw BibTeX"),
+			})
+			continue
+		}
+		errs = append(errs, checkEntry(idx, raw, entry)...)
+		added, _ := entryAdded(entry) // checkEntry reports errors.
+		bibEntries = append(bibEntries, Entry{
+			BibEntry:  *entry,
+			RawBibtex: raw.raw,
+			Added:     added,
+		})
+	}
+	if err := joinErrors(errs); err != nil {
+		return nil, err
+	}
+
+	return bibEntries, nil
+}
+
+// rawBibEntry is an entry's raw record in the .bib file, along with the byte
+// offset at which it starts.
+type rawBibEntry struct {
+	raw          string
+	offset       int
+	unterminated bool
+}
+
+func extractRawBibEntries(path string, contents []byte) (map[string]rawBibEntry, error) {
+	idx := newLineIndex(path, contents)
+	rawByCiteName := make(map[string]rawBibEntry)
+	errs := []*Error{}
+	for _, entry := range scanRawBibEntries(contents) {
+		if entry.unterminated || !isEntry(entry.raw) {
+			continue
+		}
+		citeName, ok := extractCiteName(entry.raw)
+		if !ok {
+			errs = append(errs, idx.errorf(entry.offset, "", "", "failed to extract cite name"))
+			continue
+		}
+		if _, ok := rawByCiteName[citeName]; ok {
+			errs = append(errs, idx.errorf(citeNameOffset(entry), citeName, "", "duplicate cite name"))
+			continue
+		}
+		rawByCiteName[citeName] = entry
+	}
+	return rawByCiteName, joinErrors(errs)
+}
+
+func scanRawBibEntries(contents []byte) []rawBibEntry {
+	entries := []rawBibEntry{}
+	for i := 0; i < len(contents); i++ {
+		if contents[i] != '@' {
+			continue
+		}
+
+		depth := 0
+		for j := i; j < len(contents); j++ {
+			switch contents[j] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					entries = append(entries, rawBibEntry{
+						raw:    strings.TrimSpace(string(contents[i : j+1])),
+						offset: i,
+					})
+					i = j
+					goto nextEntry
+				}
+			}
+		}
+		// We reached the end of the file without balancing all braces.
+		entries = append(entries, rawBibEntry{
+			raw:          strings.TrimSpace(string(contents[i:])),
+			offset:       i,
+			unterminated: true,
+		})
+	nextEntry:
+	}
+	return entries
+}
+
+func extractCiteName(line string) (string, bool) {
+	matches := re.FindStringSubmatch(line)
+	if len(matches) != 2 {
+		return "", false
+	}
+	return matches[1], true
+}
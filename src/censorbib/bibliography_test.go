@@ -0,0 +1,287 @@
+package censorbib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickng/bibtex"
+)
+
+func mustParse(t *testing.T, s string) Entry {
+	t.Helper()
+	bib, err := bibtex.Parse(strings.NewReader(s))
+	if err != nil {
+		t.Fatalf("failed to parse bibtex: %v", err)
+	}
+	return Entry{
+		BibEntry:  *bib.Entries[0],
+		RawBibtex: strings.TrimSpace(s),
+	}
+}
+
+func TestRun(t *testing.T) {
+	buf := bytes.NewBufferString("")
+	entry := mustParse(t, `@inproceedings{Almutairi2024a,
+		author = {Sultan Almutairi and Yogev Neumann and Khaled Harfoush},
+		title = {Fingerprinting {VPNs} with Custom Router Firmware: A New Censorship Threat Model},
+		booktitle = {Consumer Communications \& Networking Conference},
+		publisher = {IEEE},
+		year = {2024},
+		url = {https://censorbib.nymity.ch/pdf/Almutairi2024a.pdf},
+	}`)
+
+	if err := WriteEntries(buf, []Entry{entry}); err != nil {
+		t.Fatalf("failed to make bibliography: %v", err)
+	}
+
+	bufStr := buf.String()
+	if !strings.HasPrefix(bufStr, `<ul class="year-group"`) {
+		t.Errorf("expected year group <ul> but got %q...", bufStr[:30])
+	}
+	if !strings.HasSuffix(bufStr, "</ul>\n") {
+		t.Errorf("expected </ul> but got %q", bufStr[len(bufStr)-10:])
+	}
+}
+
+func TestExtractRawBibEntries(t *testing.T) {
+	contents := `@inproceedings{Doe2024a,
+	author = {Jane Doe},
+	title = {A paper with {nested} braces},
+	year = {2024},
+}
+
+@article{Müller2023a,
+	author = {Max Müller},
+	title = {Another paper},
+	year = {2023}
+}`
+
+	rawByCiteName, err := extractRawBibEntries("test.bib", []byte(contents))
+	if err != nil {
+		t.Fatalf("failed to extract raw BibTeX: %v", err)
+	}
+	if got := rawByCiteName["Doe2024a"].raw; !strings.Contains(got, `{nested}`) {
+		t.Fatalf("raw BibTeX did not preserve nested braces: %q", got)
+	}
+	if got := rawByCiteName["Müller2023a"].raw; !strings.HasPrefix(got, "@article{Müller2023a") {
+		t.Fatalf("raw BibTeX did not preserve non-ASCII cite name: %q", got)
+	}
+}
+
+func TestSortBibEntries(t *testing.T) {
+	entries := []Entry{
+		mustParse(t, `@inproceedings{Beta2024a,
+			author = {Jane Doe},
+			title = {Beta},
+			booktitle = {Workshop},
+			year = {2024},
+			url = {https://example.com/beta.pdf},
+		}`),
+		mustParse(t, `@inproceedings{Alpha2025a,
+			author = {Jane Doe},
+			title = {Alpha},
+			booktitle = {Workshop},
+			year = {2025},
+			url = {https://example.com/alpha.pdf},
+		}`),
+		// Same year, venue, and title, so the first author's last name
+		// decides, not the cite name.
+		mustParse(t, `@inproceedings{Alpha2024a,
+			author = {Max van M{\"u}ller and Jane Doe},
+			title = {Zeta},
+			booktitle = {Conference},
+			year = {2024},
+			url = {https://example.com/zeta.pdf},
+		}`),
+		mustParse(t, `@inproceedings{Zeta2024a,
+			author = {Jane Doe},
+			title = {Zeta},
+			booktitle = {Conference},
+			year = {2024},
+			url = {https://example.com/zeta.pdf},
+		}`),
+	}
+
+	sortEntries(entries)
+	got := []string{}
+	for _, entry := range entries {
+		got = append(got, entry.CiteName)
+	}
+	want := []string{"Alpha2025a", "Zeta2024a", "Alpha2024a", "Beta2024a"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected sort order: got %v, want %v", got, want)
+	}
+}
+
+func TestMakeReferenceDataScript(t *testing.T) {
+	buf := bytes.NewBufferString("")
+	entry := mustParse(t, `@inproceedings{Doe2024a,
+		author = {Jane Doe},
+		title = {Searchable Paper},
+		booktitle = {Free and Open Communications on the Internet},
+		publisher = {Example Publisher},
+		year = {2024},
+		url = {https://example.com/paper.pdf},
+	}`)
+
+	if err := WriteReferenceData(buf, []Entry{entry}); err != nil {
+		t.Fatalf("failed to make reference data: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{`"citeName":"Doe2024a"`, `"title":"Searchable Paper"`, `"publisher":"Example Publisher"`, `"rawBibtex":"@inproceedings{Doe2024a,`} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("generated metadata missing %q in %s", want, got)
+		}
+	}
+}
+
+func TestParseBibErrors(t *testing.T) {
+	contents := `@misc{Doe2024a,
+	author = {Doe, Jr, Jane, Extra},
+	title = {First paper},
+	year = {2024},
+	url = {https://example.com/first.pdf},
+}
+
+@misc{Doe2024a,
+	author = {Jane Doe},
+	title = {Second paper},
+	year = {2024},
+	url = {https://example.com/second.pdf},
+}`
+	_, err := parseBib("test.bib", []byte(contents))
+	if err == nil {
+		t.Fatal("expected parsing to fail")
+	}
+	want := `test.bib:8:7: Doe2024a: duplicate cite name`
+	if err.Error() != want {
+		t.Fatalf("Expected\n%s\ngot\n%s", want, err)
+	}
+
+	_, err = parseBib("test.bib", []byte(strings.Replace(contents, "Doe2024a", "Doe2024b", 1)))
+	var bibErr *Error
+	if !errors.As(err, &bibErr) {
+		t.Fatalf("expected an *Error but got %v", err)
+	}
+	if bibErr.Line != 2 || bibErr.Column != 2 || bibErr.CiteName != "Doe2024b" || bibErr.Field != "author" {
+		t.Fatalf("unexpected error position: %+v", bibErr)
+	}
+
+	_, err = parseBib("test.bib", []byte("@misc{Doe2024a,\n\ttitle = {First}\n\tyear = {2024},\n}"))
+	if want := "test.bib:3:5: Doe2024a: syntax error"; err == nil || err.Error() != want {
+		t.Fatalf("Expected\n%s\ngot\n%v", want, err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	bib, err := Load(strings.NewReader(`@inproceedings{Doe2024a,
+	author = {Jane Doe},
+	title = {Older Paper},
+	booktitle = {Workshop},
+	year = {2024},
+	url = {https://example.com/older.pdf},
+}
+
+@article{Doe2025a,
+	author = {Jane Doe},
+	title = {Newer Paper},
+	journal = {Journal},
+	year = {2025},
+	url = {https://example.com/newer.pdf},
+}`))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+
+	entries := bib.Entries()
+	if len(entries) != 2 || entries[0].CiteName != "Doe2024a" {
+		t.Fatalf("expected entries in file order but got %v", entries)
+	}
+	if !strings.HasPrefix(entries[1].RawBibtex, "@article{Doe2025a,") {
+		t.Fatalf("unexpected raw BibTeX: %q", entries[1].RawBibtex)
+	}
+	bib.Sort()
+	if got := bib.Entries()[0].CiteName; got != "Doe2025a" {
+		t.Fatalf("expected newest entry first but got %s", got)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := WriteHTML(buf, bib); err != nil {
+		t.Fatalf("failed to write HTML: %v", err)
+	}
+	if !strings.Contains(buf.String(), `<li id="Doe2025a">`) {
+		t.Fatal("HTML is missing entry")
+	}
+}
+
+func TestMakeScholarlyArticle(t *testing.T) {
+	entry := mustParse(t, `@article{Doe2024a,
+		author = {Jane Doe and {The Tor Project}},
+		title = {Linked Paper},
+		journal = {Privacy Enhancing Technologies},
+		volume = {2024},
+		number = {1},
+		publisher = {Example Publisher},
+		year = {2024},
+		url = {https://example.com/paper.pdf},
+	}`)
+
+	b, err := json.Marshal(linkedDataArticle{schemaOrgContext, makeScholarlyArticle(&entry)})
+	if err != nil {
+		t.Fatalf("failed to encode linked data: %v", err)
+	}
+	got := string(b)
+	for _, want := range []string{
+		`{"@context":"https://schema.org","@type":"ScholarlyArticle"`,
+		`"@id":"https://censorbib.nymity.ch/p/Doe2024a/"`,
+		`"headline":"Linked Paper"`,
+		`{"@type":"Person","name":"Jane Doe"`,
+		`{"@type":"Organization","name":"The Tor Project"}`,
+		`"@type":"PublicationIssue"`,
+		`"@type":"Periodical","name":"Privacy Enhancing Technologies"`,
+		`"sameAs":["https://censorbib-papers.t3.tigrisfiles.io/Doe2024a.pdf"]`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("linked data missing %q in %s", want, got)
+		}
+	}
+}
+
+func TestNewBadges(t *testing.T) {
+	bib, err := Load(strings.NewReader(feedBib))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+	bib.SetUpdated(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC))
+	buf := new(bytes.Buffer)
+	if err := writeEntries(buf, bib.Entries(), bib.entryOptions()); err != nil {
+		t.Fatalf("failed to write entries: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`<li id="Doe2024a" data-added="2024-01-02T00:00:00Z">`,
+		`<li id="Müller2024a" data-added="2024-06-01T00:00:00Z">` + "\n<div>\n" +
+			`<span class="paper">New Paper</span>` + "\n" + `<span class="new-badge" title="Recently added">New</span>`,
+		`<li id="Doe2024b">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("entries missing %q in %s", want, got)
+		}
+	}
+	if n := strings.Count(got, "new-badge"); n != 1 {
+		t.Errorf("expected 1 new badge but got %d", n)
+	}
+
+	bib.SetNewDays(0)
+	buf.Reset()
+	if err := writeEntries(buf, bib.Entries(), bib.entryOptions()); err != nil {
+		t.Fatalf("failed to write entries: %v", err)
+	}
+	if strings.Contains(buf.String(), "new-badge") {
+		t.Error("expected no new badges")
+	}
+}
@@ -0,0 +1,297 @@
+package censorbib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// citations is an entry formatted as a reference in the styles that the
+// BibTeX modal offers.  The citations are plain text.
+type citations struct {
+	IEEE   string `json:"ieee"`
+	ACM    string `json:"acm"`
+	APA    string `json:"apa"`
+	USENIX string `json:"usenix"`
+}
+
+// WriteCitations writes every entry's citations as a JSON object, keyed by
+// cite name, to w.  The BibTeX modal fetches them from the site when it
+// shows an entry.
+func WriteCitations(w io.Writer, bib *Bibliography) error {
+	byCiteName := make(map[string]citations)
+	for _, entry := range bib.Entries() {
+		byCiteName[entry.CiteName] = makeCitations(&entry)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(byCiteName); err != nil {
+		return fmt.Errorf("failed to encode citations: %w", err)
+	}
+	return nil
+}
+
+// citationParts are the parts of an entry that references consist of,
+// decoded.
+type citationParts struct {
+	entryType   string
+	names       []Name
+	others      bool // The author list ends in "and others".
+	title       string
+	venue       string
+	volume      string
+	number      string
+	pages       string
+	year        string
+	publisher   string
+	institution string
+	url         string
+}
+
+func makeCitations(entry *Entry) citations {
+	view := entryView(entry)
+	p := citationParts{
+		entryType:   entry.Type,
+		title:       view.Title,
+		venue:       view.Venue,
+		volume:      entryField(entry, "volume"),
+		number:      entryField(entry, "number"),
+		pages:       entryField(entry, "pages"),
+		year:        view.Year,
+		publisher:   view.Publisher,
+		institution: entryField(entry, "institution"),
+		url:         view.URL,
+	}
+	p.names, p.others = entryNames(entry)
+	return citations{
+		IEEE:   formatIEEE(p),
+		ACM:    formatACM(p),
+		APA:    formatAPA(p),
+		USENIX: formatUSENIX(p),
+	}
+}
+
+// formatIEEE formats a reference in IEEE style, e.g.: J. Doe and M. Müller,
+// "Title," in Proc. Venue, 2024, pp. 1–10.
+func formatIEEE(p citationParts) string {
+	authors := []string{}
+	for _, name := range p.names {
+		author := strings.Join(nonEmpty(initials(name), fullLastName(name)), " ")
+		if name.Jr != "" {
+			author += ", " + decodeLaTeX(name.Jr)
+		}
+		authors = append(authors, author)
+	}
+	title := `"` + p.title + `,"`
+	if strings.HasSuffix(p.title, "?") || strings.HasSuffix(p.title, "!") {
+		title = `"` + p.title + `"`
+	}
+	parts := []string{joinAuthors(authors, ", and ", " and ", p.others) + ",", title}
+	switch p.entryType {
+	case "inproceedings":
+		parts = append(parts, "in Proc. "+p.venue+",", p.year+",")
+		if p.pages != "" {
+			parts = append(parts, "pp. "+p.pages+",")
+		}
+	case "article":
+		parts = append(parts, p.venue+",")
+		if p.volume != "" {
+			parts = append(parts, "vol. "+p.volume+",")
+		}
+		if p.number != "" {
+			parts = append(parts, "no. "+p.number+",")
+		}
+		if p.pages != "" {
+			parts = append(parts, "pp. "+p.pages+",")
+		}
+		parts = append(parts, p.year+",")
+	case "techreport":
+		if p.institution != "" {
+			parts = append(parts, p.institution+",")
+		}
+		parts = append(parts, strings.Join(nonEmpty("Tech. Rep.", p.number), " ")+",", p.year+",")
+	default:
+		if p.publisher != "" {
+			parts = append(parts, p.publisher+",")
+		}
+		parts = append(parts, p.year+",")
+	}
+	return endSentence(strings.Join(parts, " ")) + " [Online]. Available: " + p.url
+}
+
+// formatACM formats a reference in ACM style, e.g.: Jane Doe and Max Müller.
+// 2024. Title. In Venue, 1–10.
+func formatACM(p citationParts) string {
+	authors := []string{}
+	for _, name := range p.names {
+		authors = append(authors, name.String())
+	}
+	sentences := []string{joinAuthors(authors, ", and ", " and ", p.others), p.year, p.title}
+	switch p.entryType {
+	case "inproceedings":
+		sentences = append(sentences, "In "+strings.Join(nonEmpty(p.venue, p.pages), ", "), p.publisher)
+	case "article":
+		venue := strings.Join(nonEmpty(p.venue, p.volume), " ")
+		if p.number != "" {
+			venue += ", " + p.number
+		}
+		sentences = append(sentences, strings.Join(nonEmpty(venue+" ("+p.year+")", p.pages), ", "), p.publisher)
+	case "techreport":
+		sentences = append(sentences, strings.Join(nonEmpty("Technical Report", p.number), " "), p.institution)
+	default:
+		sentences = append(sentences, p.publisher)
+	}
+	return joinSentences(sentences) + " " + p.url
+}
+
+// formatAPA formats a reference in APA style, e.g.: Doe, J., & Müller, M.
+// (2024). Title. In Venue (pp. 1–10). Publisher.
+func formatAPA(p citationParts) string {
+	authors := []string{}
+	for _, name := range p.names {
+		if isCorporate(name) {
+			authors = append(authors, name.String())
+			continue
+		}
+		author := fullLastName(name)
+		if first := initials(name); first != "" {
+			author += ", " + first
+		}
+		if name.Jr != "" {
+			author += ", " + decodeLaTeX(name.Jr)
+		}
+		authors = append(authors, author)
+	}
+	sentences := []string{joinAuthors(authors, ", & ", ", & ", p.others), "(" + p.year + ")"}
+	switch p.entryType {
+	case "inproceedings":
+		venue := "In " + p.venue
+		if p.pages != "" {
+			venue += " (pp. " + p.pages + ")"
+		}
+		sentences = append(sentences, p.title, venue, p.publisher)
+	case "article":
+		venue := strings.Join(nonEmpty(p.venue, p.volume), ", ")
+		if p.number != "" {
+			venue += "(" + p.number + ")"
+		}
+		sentences = append(sentences, p.title, strings.Join(nonEmpty(venue, p.pages), ", "), p.publisher)
+	case "techreport":
+		title := p.title
+		if p.number != "" {
+			title += " (Report No. " + p.number + ")"
+		}
+		sentences = append(sentences, title, p.institution)
+	default:
+		sentences = append(sentences, p.title, p.publisher)
+	}
+	return joinSentences(sentences) + " " + p.url
+}
+
+// formatUSENIX formats a reference the way USENIX proceedings do, e.g.: Jane
+// Doe and Max Müller. Title. In Venue, pages 1–10. Publisher, 2024.
+func formatUSENIX(p citationParts) string {
+	authors := []string{}
+	for _, name := range p.names {
+		authors = append(authors, name.String())
+	}
+	sentences := []string{joinAuthors(authors, ", and ", " and ", p.others), p.title}
+	switch p.entryType {
+	case "inproceedings":
+		venue := "In " + p.venue
+		if p.pages != "" {
+			venue += ", pages " + p.pages
+		}
+		sentences = append(sentences, venue, strings.Join(nonEmpty(p.publisher, p.year), ", "))
+	case "article":
+		venue := p.venue
+		if p.volume != "" {
+			venue += ", " + p.volume
+			if p.number != "" {
+				venue += "(" + p.number + ")"
+			}
+		}
+		if p.pages != "" {
+			venue += ":" + p.pages
+		}
+		sentences = append(sentences, strings.Join(nonEmpty(venue, p.year), ", "))
+	case "techreport":
+		report := strings.Join(nonEmpty("Technical Report", p.number), " ")
+		sentences = append(sentences, strings.Join(nonEmpty(report, p.institution, p.year), ", "))
+	default:
+		sentences = append(sentences, strings.Join(nonEmpty(p.publisher, p.year), ", "))
+	}
+	return joinSentences(sentences) + " " + p.url
+}
+
+// joinAuthors joins the given authors with commas, but uses last between the
+// last two of three or more authors, and pair between two authors.  If the
+// list ends in "and others", it ends in "et al." instead.
+func joinAuthors(authors []string, last, pair string, others bool) string {
+	if others {
+		return strings.Join(authors, ", ") + " et al."
+	}
+	switch len(authors) {
+	case 0:
+		return ""
+	case 1:
+		return authors[0]
+	case 2:
+		return authors[0] + pair + authors[1]
+	}
+	return strings.Join(authors[:len(authors)-1], ", ") + last + authors[len(authors)-1]
+}
+
+// joinSentences ends the given sentences in periods, unless they already end
+// in punctuation, and joins them.  Empty sentences are left out.
+func joinSentences(sentences []string) string {
+	ended := []string{}
+	for _, sentence := range nonEmpty(sentences...) {
+		ended = append(ended, endSentence(sentence))
+	}
+	return strings.Join(ended, " ")
+}
+
+// endSentence replaces the given sentence's trailing comma by a period, or
+// adds a period if it doesn't end in punctuation.
+func endSentence(s string) string {
+	s = strings.TrimSuffix(s, ",")
+	if strings.HasSuffix(s, `,"`) {
+		return strings.TrimSuffix(s, `,"`) + `."`
+	}
+	if strings.HasSuffix(s, ".") || strings.HasSuffix(s, "?") || strings.HasSuffix(s, "!") {
+		return s
+	}
+	return s + "."
+}
+
+// fullLastName returns the name's von and last parts, e.g. "van Beethoven".
+func fullLastName(name Name) string {
+	if isCorporate(name) {
+		return name.String()
+	}
+	return decodeLaTeX(strings.Join(nonEmpty(name.Von, name.Last), " "))
+}
+
+// initials returns the initials of the name's first part, e.g. "J.-P. M." for
+// "Jean-Pierre Marie".
+func initials(name Name) string {
+	words := []string{}
+	for _, word := range strings.Fields(decodeLaTeX(name.First)) {
+		hyphenated := []string{}
+		for _, part := range strings.Split(word, "-") {
+			for _, r := range part {
+				if unicode.IsLetter(r) {
+					hyphenated = append(hyphenated, string(r)+".")
+					break
+				}
+			}
+		}
+		if len(hyphenated) > 0 {
+			words = append(words, strings.Join(hyphenated, "-"))
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,134 @@
+package censorbib
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMakeCitations(t *testing.T) {
+	testCases := []struct {
+		bib  string
+		want citations
+	}{
+		{
+			bib: `@inproceedings{Doe2024a,
+				author = {Jane Doe and Ludwig van Beethoven and Jean-Pierre M{\"u}ller},
+				title = {Conference Paper},
+				booktitle = {Free and Open Communications on the Internet},
+				publisher = {Example Publisher},
+				year = {2024},
+				pages = {1--10},
+				url = {https://example.com/paper.pdf},
+			}`,
+			want: citations{
+				IEEE:   `J. Doe, L. van Beethoven, and J.-P. Müller, "Conference Paper," in Proc. Free and Open Communications on the Internet, 2024, pp. 1–10. [Online]. Available: https://example.com/paper.pdf`,
+				ACM:    `Jane Doe, Ludwig van Beethoven, and Jean-Pierre Müller. 2024. Conference Paper. In Free and Open Communications on the Internet, 1–10. Example Publisher. https://example.com/paper.pdf`,
+				APA:    `Doe, J., van Beethoven, L., & Müller, J.-P. (2024). Conference Paper. In Free and Open Communications on the Internet (pp. 1–10). Example Publisher. https://example.com/paper.pdf`,
+				USENIX: `Jane Doe, Ludwig van Beethoven, and Jean-Pierre Müller. Conference Paper. In Free and Open Communications on the Internet, pages 1–10. Example Publisher, 2024. https://example.com/paper.pdf`,
+			},
+		},
+		{
+			bib: `@article{Doe2024b,
+				author = {Jane Doe and {The Tor Project}},
+				title = {Journal Paper?},
+				journal = {Privacy Enhancing Technologies},
+				volume = {2024},
+				number = {4},
+				pages = {12--34},
+				publisher = {Sciendo},
+				year = {2024},
+				url = {https://example.com/paper.pdf},
+			}`,
+			want: citations{
+				IEEE:   `J. Doe and The Tor Project, "Journal Paper?" Privacy Enhancing Technologies, vol. 2024, no. 4, pp. 12–34, 2024. [Online]. Available: https://example.com/paper.pdf`,
+				ACM:    `Jane Doe and The Tor Project. 2024. Journal Paper? Privacy Enhancing Technologies 2024, 4 (2024), 12–34. Sciendo. https://example.com/paper.pdf`,
+				APA:    `Doe, J., & The Tor Project. (2024). Journal Paper? Privacy Enhancing Technologies, 2024(4), 12–34. Sciendo. https://example.com/paper.pdf`,
+				USENIX: `Jane Doe and The Tor Project. Journal Paper? Privacy Enhancing Technologies, 2024(4):12–34, 2024. https://example.com/paper.pdf`,
+			},
+		},
+		{
+			bib: `@techreport{Doe2024c,
+				author = {Jane Doe and others},
+				title = {Report},
+				institution = {Example University},
+				number = {TR-42},
+				year = {2024},
+				url = {https://example.com/report.pdf},
+			}`,
+			want: citations{
+				IEEE:   `J. Doe et al., "Report," Example University, Tech. Rep. TR-42, 2024. [Online]. Available: https://example.com/report.pdf`,
+				ACM:    `Jane Doe et al. 2024. Report. Technical Report TR-42. Example University. https://example.com/report.pdf`,
+				APA:    `Doe, J. et al. (2024). Report (Report No. TR-42). Example University. https://example.com/report.pdf`,
+				USENIX: `Jane Doe et al. Report. Technical Report TR-42, Example University, 2024. https://example.com/report.pdf`,
+			},
+		},
+		{
+			bib: `@misc{Doe2024d,
+				author = {Doe, Jr, Jane},
+				title = {Blog post},
+				year = {2024},
+				url = {https://example.com/post},
+			}`,
+			want: citations{
+				IEEE:   `J. Doe, Jr, "Blog post," 2024. [Online]. Available: https://example.com/post`,
+				ACM:    `Jane Doe, Jr. 2024. Blog post. https://example.com/post`,
+				APA:    `Doe, J., Jr. (2024). Blog post. https://example.com/post`,
+				USENIX: `Jane Doe, Jr. Blog post. 2024. https://example.com/post`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		entry := mustParse(t, test.bib)
+		got := makeCitations(&entry)
+		for _, style := range []struct{ name, got, want string }{
+			{"IEEE", got.IEEE, test.want.IEEE},
+			{"ACM", got.ACM, test.want.ACM},
+			{"APA", got.APA, test.want.APA},
+			{"USENIX", got.USENIX, test.want.USENIX},
+		} {
+			if style.got != style.want {
+				t.Errorf("%s: %s: Expected\n%s\ngot\n%s", entry.CiteName, style.name, style.want, style.got)
+			}
+		}
+	}
+}
+
+func TestWriteCitations(t *testing.T) {
+	bib, err := Load(strings.NewReader(feedBib))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := WriteCitations(buf, bib); err != nil {
+		t.Fatalf("failed to write citations: %v", err)
+	}
+	var got map[string]citations
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed to parse citations: %v", err)
+	}
+	if len(got) != len(bib.Entries()) {
+		t.Fatalf("expected %d entries but got %d", len(bib.Entries()), len(got))
+	}
+	for _, entry := range bib.Entries() {
+		if want := makeCitations(&entry); got[entry.CiteName] != want {
+			t.Errorf("%s: expected %+v but got %+v", entry.CiteName, want, got[entry.CiteName])
+		}
+	}
+}
+
+func TestInitials(t *testing.T) {
+	for first, want := range map[string]string{
+		"Jane":        "J.",
+		"William J.":  "W. J.",
+		"Jean-Pierre": "J.-P.",
+		`{\'E}mile`:   "É.",
+		"":            "",
+	} {
+		if got := initials(Name{First: first, Last: "Doe"}); got != want {
+			t.Errorf("initials(%q): expected %q but got %q", first, want, got)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package censorbib
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/nickng/bibtex"
+)
+
+// Matches cite names that follow our <FirstAuthorLastName><Year><letter>
+// convention, e.g.: Alaraj2025a or Mixon-Baca2025a
+var citeNameConvention = regexp.MustCompile(`^(\p{L}[\p{L}-]*)(\d{4})([a-z])$`)
+
+// citeNameAllowlist maps cite names that we don't check against our
+// convention to the reason why.  These are mostly cite names that predate the
+// convention: renaming them would break existing links and cached PDFs, so we
+// leave them alone.  Don't add new entries to work around the check; rename
+// the entry instead.
+var citeNameAllowlist = map[string]string{
+	"Knockel2019a": "predates the convention; the first author is Ruohan Xiong",
+}
+
+// Cite names may use the surname as-is (e.g. Grübl2026a) or transliterated
+// to ASCII (e.g. Koepsell2004a or Filasto2012a).
+var (
+	germanFold = strings.NewReplacer(
+		"ä", "ae", "ö", "oe", "ü", "ue", "Ä", "Ae", "Ö", "Oe", "Ü", "Ue", "ß", "ss",
+	)
+	asciiFold = strings.NewReplacer(
+		"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a", "ā", "a", "ă", "a", "ą", "a",
+		"Á", "A", "À", "A", "Â", "A", "Ä", "A", "Ã", "A", "Å", "A", "Ā", "A", "Ă", "A", "Ą", "A",
+		"ç", "c", "ć", "c", "č", "c", "Ç", "C", "Ć", "C", "Č", "C",
+		"ď", "d", "đ", "d", "Ď", "D", "Đ", "D",
+		"é", "e", "è", "e", "ê", "e", "ë", "e", "ē", "e", "ę", "e", "ě", "e",
+		"É", "E", "È", "E", "Ê", "E", "Ë", "E", "Ē", "E", "Ę", "E", "Ě", "E",
+		"ğ", "g", "Ğ", "G",
+		"í", "i", "ì", "i", "î", "i", "ï", "i", "ī", "i", "ı", "i",
+		"Í", "I", "Ì", "I", "Î", "I", "Ï", "I", "Ī", "I", "İ", "I",
+		"ł", "l", "Ł", "L",
+		"ñ", "n", "ń", "n", "ň", "n", "Ñ", "N", "Ń", "N", "Ň", "N",
+		"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o", "ō", "o", "ő", "o",
+		"Ó", "O", "Ò", "O", "Ô", "O", "Ö", "O", "Õ", "O", "Ø", "O", "Ō", "O", "Ő", "O",
+		"ř", "r", "Ř", "R",
+		"ś", "s", "š", "s", "ş", "s", "ß", "ss", "Ś", "S", "Š", "S", "Ş", "S",
+		"ť", "t", "ţ", "t", "Ť", "T", "Ţ", "T",
+		"ú", "u", "ù", "u", "û", "u", "ü", "u", "ū", "u", "ů", "u", "ű", "u",
+		"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U", "Ū", "U", "Ů", "U", "Ű", "U",
+		"ý", "y", "ÿ", "y", "Ý", "Y",
+		"ź", "z", "ż", "z", "ž", "z", "Ź", "Z", "Ż", "Z", "Ž", "Z",
+	)
+)
+
+// citeNameViolation is a cite name that does not follow our convention,
+// along with the cite name that we suggest instead.
+type citeNameViolation struct {
+	entry      *bibtex.BibEntry
+	msg        string
+	suggestion string
+}
+
+func (v citeNameViolation) String() string {
+	if v.suggestion == "" {
+		return v.msg
+	}
+	return fmt.Sprintf("%s; try %s", v.msg, v.suggestion)
+}
+
+// checkCiteNames checks the entries' cite names against our convention,
+// skipping the cite names in the given allowlist.
+func checkCiteNames(entries []*bibtex.BibEntry, allowlist map[string]string) []citeNameViolation {
+	taken := make(map[string]bool)
+	for _, entry := range entries {
+		// Cite names that only differ in case would still clash.
+		taken[strings.ToLower(entry.CiteName)] = true
+	}
+
+	violations := []citeNameViolation{}
+	lettersByGroup := make(map[string][]string)
+	for _, entry := range entries {
+		matches := citeNameConvention.FindStringSubmatch(entry.CiteName)
+		if matches != nil {
+			group := strings.ToLower(matches[1]) + matches[2]
+			lettersByGroup[group] = append(lettersByGroup[group], matches[3])
+		}
+		if _, ok := allowlist[entry.CiteName]; ok || isParent(entry) {
+			continue
+		}
+
+		authors := toStr(entry.Fields["author"])
+		year := strings.TrimSpace(toStr(entry.Fields["year"]))
+		if authors == "" || year == "" || validateAuthors(authors) != nil {
+			continue // Other checks report these problems.
+		}
+		surname := firstAuthorSurname(authors)
+		prefixes := citeNamePrefixes(surname)
+		suggestion := nextFreeCiteName(prefixes[0], year, taken)
+
+		var msg string
+		switch {
+		case matches == nil:
+			msg = "cite name does not follow the <FirstAuthorLastName><Year><letter> convention"
+		case matches[2] != year:
+			msg = fmt.Sprintf("cite name has year %s but the entry's year is %s", matches[2], year)
+		case !hasPrefix(prefixes, matches[1]):
+			msg = fmt.Sprintf("cite name does not start with the first author's last name %q", surname)
+		default:
+			continue
+		}
+		violations = append(violations, citeNameViolation{entry, msg, suggestion})
+	}
+
+	// Letter suffixes for the same author and year must be sequential, i.e.,
+	// there can be no Doe2024b without Doe2024a, and no two entries may use
+	// the same letter, e.g. Doe2024a and DOE2024a.
+	usedBy := make(map[string]string)
+	for _, entry := range entries {
+		matches := citeNameConvention.FindStringSubmatch(entry.CiteName)
+		if matches == nil {
+			continue
+		}
+		if _, ok := allowlist[entry.CiteName]; ok {
+			continue
+		}
+		group := strings.ToLower(matches[1]) + matches[2]
+		if first, ok := usedBy[group+matches[3]]; ok {
+			// Identical cite names are reported as duplicates.
+			if first != entry.CiteName {
+				violations = append(violations, citeNameViolation{
+					entry,
+					fmt.Sprintf("letter suffix %q is already used by %s", matches[3], first),
+					nextFreeCiteName(matches[1], matches[2], taken),
+				})
+			}
+			continue
+		}
+		usedBy[group+matches[3]] = entry.CiteName
+
+		letters := uniqueLetters(lettersByGroup[group])
+		for i, letter := range letters {
+			if letter != matches[3] {
+				continue
+			}
+			if want := string(rune('a' + i)); letter != want {
+				violations = append(violations, citeNameViolation{
+					entry,
+					fmt.Sprintf("letter suffix %q skips %q", letter, want),
+					nextFreeCiteName(matches[1], matches[2], taken),
+				})
+			}
+			break
+		}
+	}
+
+	return violations
+}
+
+// uniqueLetters returns the given letter suffixes sorted and without
+// duplicates.
+func uniqueLetters(letters []string) []string {
+	unique := slices.Clone(letters)
+	sort.Strings(unique)
+	return slices.Compact(unique)
+}
+
+// firstAuthorSurname returns the decoded last name of an entry's first
+// author, e.g. "Mohajeri Moghaddam" for "Hooman {Mohajeri Moghaddam}".
+func firstAuthorSurname(authors string) string {
+	names, err := ParseAuthors(authors)
+	if err != nil {
+		return ""
+	}
+	return names[0].LastName()
+}
+
+// citeNamePrefixes returns the cite name prefixes that we accept for the given
+// surname.  The first one is the prefix that we suggest.
+func citeNamePrefixes(surname string) []string {
+	variants := []string{lettersOnly(surname)}
+	if strings.Contains(surname, "-") {
+		variants = append(variants, lettersOnly(strings.ReplaceAll(surname, "-", "")))
+	}
+	words := strings.FieldsFunc(surname, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-'
+	})
+	if len(words) > 1 {
+		variants = append(variants, lettersOnly(words[len(words)-1]))
+	}
+
+	prefixes := []string{}
+	for _, variant := range variants {
+		prefixes = append(prefixes, variant, germanFold.Replace(variant), asciiFold.Replace(variant))
+	}
+	return prefixes
+}
+
+// lettersOnly removes everything but letters and hyphens from the given
+// surname, and capitalizes its first letter, e.g. "THooft" for "'t Hooft".
+func lettersOnly(s string) string {
+	var b strings.Builder
+	capitalized := false
+	for _, r := range s {
+		if !unicode.IsLetter(r) && r != '-' {
+			continue
+		}
+		if !capitalized && unicode.IsLetter(r) {
+			r = unicode.ToUpper(r)
+			capitalized = true
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func hasPrefix(prefixes []string, prefix string) bool {
+	for _, p := range prefixes {
+		if strings.EqualFold(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextFreeCiteName returns the first cite name for the given prefix and year
+// that is not yet taken, ignoring case, e.g. Doe2024c if Doe2024a and
+// Doe2024b exist.
+func nextFreeCiteName(prefix, year string, taken map[string]bool) string {
+	for letter := 'a'; letter <= 'z'; letter++ {
+		citeName := fmt.Sprintf("%s%s%c", prefix, year, letter)
+		if !taken[strings.ToLower(citeName)] {
+			return citeName
+		}
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package censorbib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickng/bibtex"
+)
+
+func TestCheckCiteNames(t *testing.T) {
+	bib, err := bibtex.Parse(strings.NewReader(`
+@misc{Doe2024a, author = {Jane Doe}, year = {2024}}
+@misc{Doe2024c, author = {Jane Doe and John Doe}, year = {2024}}
+@misc{Doe2023a, author = {Jane Doe}, year = {2024}}
+@misc{Smith2024a, author = {Jane Doe}, year = {2024}}
+@misc{Grübl2026a, author = {Thomas Grübl}, year = {2026}}
+@misc{Koepsell2004a, author = {Stefan Köpsell}, year = {2004}}
+@misc{Filasto2012a, author = {Arturo Filastò}, year = {2012}}
+@misc{Moghaddam2012a, author = {Hooman {Mohajeri Moghaddam}}, year = {2012}}
+@misc{Mixon-Baca2025a, author = {Benjamin Mixon-Baca}, year = {2025}}
+@misc{Sharma2025a, author = {Piyush Kumar Sharma and Harry}, year = {2025}}
+@misc{paper1, author = {Max Müller}, year = {2023}}
+@misc{Roe2020a, author = {Richard Roe}, year = {2020}}
+@misc{ROE2020a, author = {Richard Roe}, year = {2020}}
+@misc{Roe2020b, author = {Richard Roe}, year = {2020}}
+@misc{Roe2020b, author = {Richard Roe}, year = {2020}}
+@misc{Legacy2019a, author = {Richard Roe}, year = {2019}}
+`))
+	if err != nil {
+		t.Fatalf("failed to parse bibtex: %v", err)
+	}
+
+	var got []string
+	allowlist := map[string]string{"Legacy2019a": "predates the convention"}
+	for _, v := range checkCiteNames(bib.Entries, allowlist) {
+		got = append(got, v.entry.CiteName+": "+v.String())
+	}
+	want := []string{
+		"Doe2023a: cite name has year 2023 but the entry's year is 2024; try Doe2024b",
+		`Smith2024a: cite name does not start with the first author's last name "Doe"; try Doe2024b`,
+		"paper1: cite name does not follow the <FirstAuthorLastName><Year><letter> convention; try Müller2023a",
+		`Doe2024c: letter suffix "c" skips "b"; try Doe2024b`,
+		`ROE2020a: letter suffix "a" is already used by Roe2020a; try ROE2020c`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected violations:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestFirstAuthorSurname(t *testing.T) {
+	testCases := []conversion{
+		{from: "Jane Doe", to: "Doe"},
+		{from: "Jane Doe and John Doe", to: "Doe"},
+		{from: "Hooman {Mohajeri Moghaddam} and Baiyu Li", to: "Mohajeri Moghaddam"},
+		{from: "Harry", to: "Harry"},
+		{from: "Thomas Grübl", to: "Grübl"},
+		{from: "Doe, Jane and John Roe", to: "Doe"},
+		{from: "Ludwig van Beethoven", to: "Beethoven"},
+		{from: `Kurt G{\"o}del`, to: "Gödel"},
+	}
+
+	for _, test := range testCases {
+		if got := firstAuthorSurname(test.from); got != test.to {
+			t.Errorf("firstAuthorSurname(%q): expected %q, got %q", test.from, test.to, got)
+		}
+	}
+}
+
+func TestLettersOnly(t *testing.T) {
+	testCases := []conversion{
+		{from: "Doe", to: "Doe"},
+		{from: "grübl", to: "Grübl"},
+		{from: "élise", to: "Élise"},
+		{from: "'t Hooft", to: "THooft"},
+		{from: "Mixon-Baca", to: "Mixon-Baca"},
+	}
+
+	for _, test := range testCases {
+		if got := lettersOnly(test.from); got != test.to {
+			t.Errorf("lettersOnly(%q): expected %q, got %q", test.from, test.to, got)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package censorbib
+
+import (
+	"net/url"
+	"strings"
+)
+
+// coinsContext returns the given entry as an OpenURL ContextObject in
+// key-encoded-value format, which is what the title attribute of a COinS span
+// contains.  Reference managers like Zotero look for these spans, so that
+// users can import papers straight from the page.  Empty values are left out.
+func coinsContext(entry *Entry) string {
+	view := entryView(entry)
+	values := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			values.Add(key, value)
+		}
+	}
+
+	set("ctx_ver", "Z39.88-2004")
+	switch entry.Type {
+	case "article":
+		set("rft_val_fmt", "info:ofi/fmt:kev:mtx:journal")
+		set("rft.genre", "article")
+		set("rft.atitle", view.Title)
+		set("rft.jtitle", view.Venue)
+		set("rft.volume", entryField(entry, "volume"))
+		set("rft.issue", entryField(entry, "number"))
+	case "inproceedings":
+		set("rft_val_fmt", "info:ofi/fmt:kev:mtx:book")
+		set("rft.genre", "proceeding")
+		set("rft.atitle", view.Title)
+		set("rft.btitle", view.Venue)
+	case "techreport":
+		set("rft_val_fmt", "info:ofi/fmt:kev:mtx:book")
+		set("rft.genre", "report")
+		set("rft.btitle", view.Title)
+		set("rft.pub", entryField(entry, "institution"))
+	default:
+		set("rft_val_fmt", "info:ofi/fmt:kev:mtx:book")
+		set("rft.genre", "document")
+		set("rft.btitle", view.Title)
+	}
+
+	names, _ := entryNames(entry)
+	for i, name := range names {
+		if i == 0 {
+			set("rft.aulast", decodeLaTeX(strings.Join(nonEmpty(name.Von, name.Last), " ")))
+			set("rft.aufirst", decodeLaTeX(name.First))
+		}
+		set("rft.au", name.String())
+	}
+	set("rft.date", view.Year)
+	firstPage, lastPage := splitPages(toStr(entry.Fields["pages"]))
+	set("rft.spage", firstPage)
+	set("rft.epage", lastPage)
+	set("rft.pub", view.Publisher)
+	set("rft_id", view.URL)
+	return values.Encode()
+}
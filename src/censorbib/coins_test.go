@@ -0,0 +1,75 @@
+package censorbib
+
+import (
+	"bytes"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCOinSContext(t *testing.T) {
+	entry := mustParse(t, `@article{Doe2024a,
+		author = {Jane Doe and Ludwig van Beethoven and others},
+		title = {Importable {Paper}},
+		journal = {Privacy Enhancing Technologies},
+		volume = {2024},
+		number = {1},
+		pages = {12--34},
+		publisher = {Example Publisher},
+		year = {2024},
+		url = {https://example.com/paper.pdf},
+	}`)
+
+	got, err := url.ParseQuery(coinsContext(&entry))
+	if err != nil {
+		t.Fatalf("failed to parse context object: %v", err)
+	}
+	want := url.Values{
+		"ctx_ver":     {"Z39.88-2004"},
+		"rft_val_fmt": {"info:ofi/fmt:kev:mtx:journal"},
+		"rft.genre":   {"article"},
+		"rft.atitle":  {"Importable Paper"},
+		"rft.jtitle":  {"Privacy Enhancing Technologies"},
+		"rft.volume":  {"2024"},
+		"rft.issue":   {"1"},
+		"rft.aulast":  {"Doe"},
+		"rft.aufirst": {"Jane"},
+		"rft.au":      {"Jane Doe", "Ludwig van Beethoven"},
+		"rft.date":    {"2024"},
+		"rft.spage":   {"12"},
+		"rft.epage":   {"34"},
+		"rft.pub":     {"Example Publisher"},
+		"rft_id":      {"https://example.com/paper.pdf"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected context object:\ngot  %v\nwant %v", got, want)
+	}
+}
+
+func TestWriteEntriesCOinS(t *testing.T) {
+	entry := mustParse(t, `@inproceedings{Doe2024a,
+		author = {Jane Doe},
+		title = {Importable Paper},
+		booktitle = {Free and Open Communications on the Internet},
+		year = {2024},
+		url = {https://example.com/paper.pdf},
+	}`)
+
+	buf := new(bytes.Buffer)
+	if err := WriteEntries(buf, []Entry{entry}); err != nil {
+		t.Fatalf("failed to write entries: %v", err)
+	}
+	if strings.Contains(buf.String(), "Z3988") {
+		t.Errorf("COinS span embedded by default: %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeEntries(buf, []Entry{entry}, entryOptions{coins: true}); err != nil {
+		t.Fatalf("failed to write entries: %v", err)
+	}
+	want := `<span class="Z3988" title="ctx_ver=Z39.88-2004&amp;rft.atitle=Importable&#43;Paper&amp;rft.au=Jane&#43;Doe&amp;`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("COinS span missing %q in %s", want, buf.String())
+	}
+}
@@ -0,0 +1,164 @@
+package censorbib
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nickng/bibtex"
+)
+
+// Fields that an entry never inherits from its parent, because they describe
+// the paper rather than the proceedings that it appeared in.
+var uninheritedFields = map[string]bool{
+	"author":         true,
+	"title":          true,
+	"url":            true,
+	"discussion_url": true,
+	"pages":          true,
+	"crossref":       true,
+	"abstract":       true,
+	"added":          true,
+}
+
+// crossrefViolation is a problem with an entry's crossref field.
+type crossrefViolation struct {
+	entry *bibtex.BibEntry
+	msg   string
+}
+
+// isParent reports whether the given entry is a parent entry, like
+// @proceedings, which we don't render as a paper.
+func isParent(entry *bibtex.BibEntry) bool {
+	return entrySchemas[entry.Type].parent
+}
+
+// crossrefTarget returns the cite name that the entry's crossref field refers
+// to, if any.
+func crossrefTarget(entry *bibtex.BibEntry) (string, bool) {
+	crossref, ok := entry.Fields["crossref"]
+	if !ok {
+		return "", false
+	}
+	return strings.TrimSpace(toStr(crossref)), true
+}
+
+// resolveCrossrefs copies the fields that entries with a crossref field lack
+// from their parent entries, the way BibTeX does.  Parents must be
+// @proceedings entries, so a paper can't inherit another paper's fields.
+func resolveCrossrefs(entries []*bibtex.BibEntry) []crossrefViolation {
+	byCiteName := make(map[string]*bibtex.BibEntry)
+	for _, entry := range entries {
+		byCiteName[entry.CiteName] = entry
+	}
+
+	violations := []crossrefViolation{}
+	for _, entry := range entries {
+		target, ok := crossrefTarget(entry)
+		if !ok {
+			continue
+		}
+		parent, ok := byCiteName[target]
+		switch {
+		case !ok:
+			violations = append(violations, crossrefViolation{entry, fmt.Sprintf("crossref to unknown entry %q", target)})
+			continue
+		case !isParent(parent):
+			violations = append(violations, crossrefViolation{entry, fmt.Sprintf("crossref to %q, which is not a @proceedings entry", target)})
+			continue
+		}
+		for field, parentField := range inheritedFields(entry.Type, fieldNames(entry), fieldNames(parent)) {
+			entry.Fields[field] = parent.Fields[parentField]
+		}
+	}
+	return violations
+}
+
+// inheritedFields maps the fields that an entry of the given type inherits
+// from its parent to the parent's fields that they come from.  An entry
+// inherits the fields that it lacks and that its type allows.  The parent's
+// title becomes the entry's booktitle, unless the parent has a booktitle of
+// its own.
+func inheritedFields(entryType string, entryFields, parentFields []string) map[string]string {
+	has := make(map[string]bool)
+	for _, field := range entryFields {
+		has[field] = true
+	}
+	allowed := entrySchemas[entryType].allowed()
+
+	inherited := make(map[string]string)
+	for _, field := range parentFields {
+		if !uninheritedFields[field] && allowed[field] && !has[field] {
+			inherited[field] = field
+		}
+	}
+	if _, ok := inherited["booktitle"]; !ok && allowed["booktitle"] && !has["booktitle"] {
+		for _, field := range parentFields {
+			if field == "title" {
+				inherited["booktitle"] = "title"
+			}
+		}
+	}
+	return inherited
+}
+
+func fieldNames(entry *bibtex.BibEntry) []string {
+	names := []string{}
+	for name := range entry.Fields {
+		names = append(names, name)
+	}
+	return names
+}
+
+// inlineRawCrossref returns the given raw entry with the fields that it
+// inherits from its raw parent entry copied into it, and without its
+// crossref field.  The result is an entry that stands on its own.
+func inlineRawCrossref(raw, parentRaw string) string {
+	header := entryHeaderRe.FindStringSubmatch(raw)
+	parentHeader := entryHeaderRe.FindString(parentRaw)
+	if header == nil || parentHeader == "" {
+		return raw
+	}
+	fields, err := splitRawFields(raw[len(header[0]):])
+	if err != nil {
+		return raw
+	}
+	parentFields, err := splitRawFields(parentRaw[len(parentHeader):])
+	if err != nil {
+		return raw
+	}
+
+	inlined := []rawField{}
+	for _, field := range fields {
+		if field.name != "crossref" {
+			inlined = append(inlined, field)
+		}
+	}
+	entryType := strings.ToLower(header[1])
+	// Every parent field is inherited at most once, so we can look up fields
+	// by the parent field they come from.
+	inheritedFrom := make(map[string]string)
+	for field, parentField := range inheritedFields(entryType, rawFieldNames(fields), rawFieldNames(parentFields)) {
+		inheritedFrom[parentField] = field
+	}
+	for _, parentField := range parentFields {
+		if field, ok := inheritedFrom[parentField.name]; ok {
+			inlined = append(inlined, rawField{name: field, value: parentField.value})
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "@%s{%s,\n", entryType, header[2])
+	for _, field := range sortRawFields(inlined) {
+		fmt.Fprintf(&b, "\t%s = %s,\n", field.name, field.value)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func rawFieldNames(fields []rawField) []string {
+	names := []string{}
+	for _, field := range fields {
+		names = append(names, field.name)
+	}
+	return names
+}
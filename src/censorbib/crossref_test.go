@@ -0,0 +1,90 @@
+package censorbib
+
+import (
+	"strings"
+	"testing"
+)
+
+const crossrefBib = `@inproceedings{Doe2025a,
+	author = {Jane Doe},
+	title = {Paper},
+	pages = {1--10},
+	url = {https://example.com/paper.pdf},
+	crossref = {PETS2025},
+}
+
+@proceedings{PETS2025,
+	title = {Privacy Enhancing Technologies},
+	publisher = {PETS Advisory Board},
+	year = {2025},
+	url = {https://petsymposium.org/2025/},
+}
+`
+
+func TestLoadCrossrefs(t *testing.T) {
+	bib, err := Load(strings.NewReader(crossrefBib))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+	entries := bib.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("expected parents not to be entries, but got %d entries", len(entries))
+	}
+	entry := &entries[0]
+	for field, want := range map[string]string{
+		"booktitle": "Privacy Enhancing Technologies",
+		"publisher": "PETS Advisory Board",
+		"year":      "2025",
+		"url":       "https://example.com/paper.pdf",
+	} {
+		if got := toStr(entry.Fields[field]); got != want {
+			t.Errorf("%s: expected %q, got %q", field, want, got)
+		}
+	}
+	if _, ok := entry.Fields["title"]; !ok || toStr(entry.Fields["title"]) != "Paper" {
+		t.Errorf("expected the entry to keep its own title")
+	}
+
+	bib.InlineCrossrefs()
+	want := `@inproceedings{Doe2025a,
+	author = {Jane Doe},
+	title = {Paper},
+	booktitle = {Privacy Enhancing Technologies},
+	publisher = {PETS Advisory Board},
+	year = {2025},
+	pages = {1--10},
+	url = {https://example.com/paper.pdf},
+}`
+	if got := bib.Entries()[0].RawBibtex; got != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestCrossrefErrors(t *testing.T) {
+	contents := strings.Replace(crossrefBib, "{PETS2025},\n}", "{PETS2024},\n}", 1)
+	want := `test.bib:6:2: Doe2025a: crossref: crossref to unknown entry "PETS2024"`
+	if _, err := parseBib("test.bib", []byte(contents)); err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("Expected\n%s\ngot\n%v", want, err)
+	}
+
+	contents = crossrefBib + `
+@inproceedings{Roe2025a,
+	author = {John Roe},
+	title = {Another paper},
+	url = {https://example.com/another.pdf},
+	crossref = {Doe2025a},
+}
+`
+	var got []string
+	for _, err := range Lint("test.bib", []byte(contents)) {
+		got = append(got, err.Error())
+	}
+	wantLint := []string{
+		"test.bib:16:1: Roe2025a: booktitle: missing required field",
+		"test.bib:16:1: Roe2025a: year: missing required field",
+		`test.bib:20:2: Roe2025a: crossref: crossref to "Doe2025a", which is not a @proceedings entry`,
+	}
+	if strings.Join(got, "\n") != strings.Join(wantLint, "\n") {
+		t.Fatalf("unexpected diagnostics:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(wantLint, "\n"))
+	}
+}
@@ -0,0 +1,141 @@
+package censorbib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cslItem is an entry in CSL-JSON, the format that citeproc, Pandoc, and
+// Zotero use.  See https://citeproc-js.readthedocs.io/en/latest/csl-json/markup.html
+type cslItem struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	Title          string    `json:"title"`
+	Author         []cslName `json:"author,omitempty"`
+	Issued         *cslDate  `json:"issued,omitempty"`
+	ContainerTitle string    `json:"container-title,omitempty"`
+	Volume         string    `json:"volume,omitempty"`
+	Issue          string    `json:"issue,omitempty"`
+	Number         string    `json:"number,omitempty"`
+	Page           string    `json:"page,omitempty"`
+	Publisher      string    `json:"publisher,omitempty"`
+	Note           string    `json:"note,omitempty"`
+	URL            string    `json:"URL,omitempty"`
+}
+
+// cslName is an author.  Corporate authors only have a literal name.
+type cslName struct {
+	Family              string `json:"family,omitempty"`
+	Given               string `json:"given,omitempty"`
+	NonDroppingParticle string `json:"non-dropping-particle,omitempty"`
+	Suffix              string `json:"suffix,omitempty"`
+	Literal             string `json:"literal,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts,omitempty"`
+	Literal   string  `json:"literal,omitempty"`
+}
+
+// The CSL types of our entry types.
+var cslTypes = map[string]string{
+	"inproceedings": "paper-conference",
+	"article":       "article-journal",
+	"techreport":    "report",
+	"misc":          "document",
+}
+
+// WriteCSLJSON writes the bibliography as a CSL-JSON array to w.  The
+// bibliography is sorted first.
+func WriteCSLJSON(w io.Writer, bib *Bibliography) error {
+	bib.Sort()
+	items := []cslItem{}
+	for _, entry := range bib.Entries() {
+		items = append(items, makeCSLItem(&entry))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(items); err != nil {
+		return fmt.Errorf("failed to encode CSL-JSON: %w", err)
+	}
+	return nil
+}
+
+func makeCSLItem(entry *Entry) cslItem {
+	view := entryView(entry)
+	item := cslItem{
+		ID:             entry.CiteName,
+		Type:           cslTypes[entry.Type],
+		Title:          view.Title,
+		Issued:         makeCSLDate(view.Year, entryField(entry, "month")),
+		ContainerTitle: view.Venue,
+		Volume:         entryField(entry, "volume"),
+		Issue:          entryField(entry, "number"),
+		Page:           strings.ReplaceAll(toStr(entry.Fields["pages"]), "--", "-"),
+		Publisher:      entryInstitutionOrPublisher(entry),
+		Note:           entryField(entry, "note"),
+		URL:            view.URL,
+	}
+	if entry.Type == "techreport" {
+		// A report's number isn't an issue.
+		item.Issue, item.Number = "", item.Issue
+	}
+	names, _ := entryNames(entry)
+	for _, name := range names {
+		item.Author = append(item.Author, makeCSLName(name))
+	}
+	return item
+}
+
+// makeCSLName turns an author into a CSL name, unless the author is a braced
+// corporate name like {The Tor Project}, which is kept as-is.
+func makeCSLName(name Name) cslName {
+	if isCorporate(name) {
+		return cslName{Literal: name.String()}
+	}
+	return cslName{
+		Family:              decodeLaTeX(name.Last),
+		Given:               decodeLaTeX(name.First),
+		NonDroppingParticle: decodeLaTeX(name.Von),
+		Suffix:              decodeLaTeX(name.Jr),
+	}
+}
+
+// makeCSLDate returns the given year and month as a CSL date.  Years that
+// aren't numbers are kept as literal.
+func makeCSLDate(year, month string) *cslDate {
+	if year == "" {
+		return nil
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return &cslDate{Literal: year}
+	}
+	parts := []int{y}
+	if m := monthNumber(month); m > 0 {
+		parts = append(parts, m)
+	}
+	return &cslDate{DateParts: [][]int{parts}}
+}
+
+// monthNumber returns the number of the given month, e.g. 3 for "March",
+// "mar", or "3", and 0 if it isn't a month.
+func monthNumber(month string) int {
+	month = strings.TrimSpace(month)
+	if m, err := strconv.Atoi(month); err == nil && m >= 1 && m <= 12 {
+		return m
+	}
+	if len(month) < 3 {
+		return 0
+	}
+	for m := time.January; m <= time.December; m++ {
+		if strings.EqualFold(month[:3], m.String()[:3]) {
+			return int(m)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,132 @@
+package censorbib
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMakeCSLItem(t *testing.T) {
+	testCases := []struct {
+		bib  string
+		want cslItem
+	}{
+		{
+			bib: `@inproceedings{Doe2024a,
+				author = {Doe, Jr, Jane and Ludwig van Beethoven and others},
+				title = {Conference {Paper}},
+				booktitle = {Free and Open Communications on the Internet},
+				publisher = {Example Publisher},
+				month = {March},
+				year = {2024},
+				pages = {1--10},
+				url = {https://example.com/paper.pdf},
+			}`,
+			want: cslItem{
+				ID:    "Doe2024a",
+				Type:  "paper-conference",
+				Title: "Conference Paper",
+				Author: []cslName{
+					{Family: "Doe", Given: "Jane", Suffix: "Jr"},
+					{Family: "Beethoven", Given: "Ludwig", NonDroppingParticle: "van"},
+				},
+				Issued:         &cslDate{DateParts: [][]int{{2024, 3}}},
+				ContainerTitle: "Free and Open Communications on the Internet",
+				Page:           "1-10",
+				Publisher:      "Example Publisher",
+				URL:            "https://example.com/paper.pdf",
+			},
+		},
+		{
+			bib: `@article{Doe2024b,
+				author = {{The Tor Project}},
+				title = {Journal Paper},
+				journal = {Privacy Enhancing Technologies},
+				volume = {2024},
+				number = {4},
+				year = {2024},
+				url = {https://example.com/paper.pdf},
+			}`,
+			want: cslItem{
+				ID:             "Doe2024b",
+				Type:           "article-journal",
+				Title:          "Journal Paper",
+				Author:         []cslName{{Literal: "The Tor Project"}},
+				Issued:         &cslDate{DateParts: [][]int{{2024}}},
+				ContainerTitle: "Privacy Enhancing Technologies",
+				Volume:         "2024",
+				Issue:          "4",
+				URL:            "https://example.com/paper.pdf",
+			},
+		},
+		{
+			bib: `@techreport{Doe2024c,
+				author = {Jane Doe},
+				title = {Report},
+				institution = {Example University},
+				number = {TR-42},
+				year = {2024},
+				url = {https://example.com/report.pdf},
+			}`,
+			want: cslItem{
+				ID:        "Doe2024c",
+				Type:      "report",
+				Title:     "Report",
+				Author:    []cslName{{Family: "Doe", Given: "Jane"}},
+				Issued:    &cslDate{DateParts: [][]int{{2024}}},
+				Number:    "TR-42",
+				Publisher: "Example University",
+				URL:       "https://example.com/report.pdf",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		entry := mustParse(t, test.bib)
+		if got := makeCSLItem(&entry); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Expected\n%+v\ngot\n%+v", test.want, got)
+		}
+	}
+}
+
+func TestMonthNumber(t *testing.T) {
+	for month, want := range map[string]int{
+		"March": 3,
+		"mar":   3,
+		"12":    12,
+		"13":    0,
+		"Fall":  0,
+		"":      0,
+	} {
+		if got := monthNumber(month); got != want {
+			t.Errorf("monthNumber(%q): expected %d but got %d", month, want, got)
+		}
+	}
+}
+
+func TestWriteCSLJSON(t *testing.T) {
+	bib, err := Load(strings.NewReader(feedBib))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := WriteCSLJSON(buf, bib); err != nil {
+		t.Fatalf("failed to write CSL-JSON: %v", err)
+	}
+	var items []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+		t.Fatalf("failed to parse CSL-JSON: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items but got %d", len(items))
+	}
+	for _, item := range items {
+		for _, key := range []string{"id", "type", "title", "author", "issued", "URL"} {
+			if _, ok := item[key]; !ok {
+				t.Errorf("item %v lacks %s", item["id"], key)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package censorbib
+
+import (
+	"strings"
+)
+
+type conversion struct {
+	from string
+	to   string
+}
+
+// DecodeTitle turns a title's LaTeX into Unicode text.  Curly brackets are
+// removed because we're displaying titles without changing their casing.
+func DecodeTitle(title string) string {
+	return decodeLaTeX(title)
+}
+
+// DecodeAuthors turns an author list's LaTeX into Unicode text, with authors
+// separated by commas.
+func DecodeAuthors(authors string) string {
+	names, err := ParseAuthors(authors)
+	if err != nil {
+		// validateAuthors rejects these when parsing the .bib file, so this
+		// only happens for callers that skipped validation.
+		return decodeLaTeX(authors)
+	}
+	return joinNames(names)
+}
+
+func joinNames(names []Name) string {
+	display := make([]string, len(names))
+	for i, name := range names {
+		display[i] = name.String()
+	}
+	return strings.Join(display, ", ")
+}
+
+// validateAuthors checks if DecodeAuthors can make sense of the given
+// authors.  We check this when parsing the .bib file.
+func validateAuthors(authors string) error {
+	_, err := ParseAuthors(authors)
+	return err
+}
+
+// DecodeProceedings turns a venue's LaTeX into Unicode text.
+func DecodeProceedings(proceedings string) string {
+	return decodeLaTeX(proceedings)
+}
+
+// DecodePublisher turns a publisher's LaTeX into Unicode text.
+func DecodePublisher(publisher string) string {
+	return decodeLaTeX(publisher)
+}
@@ -0,0 +1,125 @@
+package censorbib
+
+import (
+	"testing"
+)
+
+func TestToString(t *testing.T) {
+	testCases := []conversion{
+		{
+			from: "Title",
+			to:   "Title",
+		},
+		{
+			from: "This is a {Title}",
+			to:   "This is a Title",
+		},
+		{
+			from: "This is a {Title}",
+			to:   "This is a Title",
+		},
+		{
+			from: `{\#h00t}: Censorship Resistant Microblogging`,
+			to:   `#h00t: Censorship Resistant Microblogging`,
+		},
+		{
+			from: "``Good'' Worms and Human Rights",
+			to:   "“Good” Worms and Human Rights",
+		},
+		{
+			from: "An Analysis of {China}'s ``{Great Cannon}''",
+			to:   "An Analysis of China’s “Great Cannon”",
+		},
+		{
+			from: `lib$\cdot$erate, (n):`,
+			to:   `lib·erate, (n):`,
+		},
+		{
+			from: "Well -- Exploring the {Great} {Firewall}'s Poisoned {DNS}",
+			to:   "Well – Exploring the Great Firewall’s Poisoned DNS",
+		},
+	}
+
+	for _, test := range testCases {
+		to := DecodeTitle(test.from)
+		if to != test.to {
+			t.Errorf("Expected\n%s\ngot\n%s", test.to, to)
+		}
+	}
+}
+
+func TestDecodeAuthors(t *testing.T) {
+	testCases := []conversion{
+		{ // Multiple authors should be separated by commas.
+			from: "John Doe and Jane Doe",
+			to:   "John Doe, Jane Doe",
+		},
+		{ // Single authors should remain as-is.
+			from: "John Doe",
+			to:   "John Doe",
+		},
+		{ // Single-name authors should remain as-is.
+			from: "John and Jane",
+			to:   "John, Jane",
+		},
+		{ // Non-ASCII characters should be unaffected.
+			from: "Jóhn Doe",
+			to:   "Jóhn Doe",
+		},
+		{ // Apostrophes should be replaced with the right single quote.
+			from: "John O'Brian",
+			to:   "John O’Brian",
+		},
+		{ // Names in "Last, First" form should be displayed as "First Last".
+			from: "Doe, Jane and Roe, John",
+			to:   "Jane Doe, John Roe",
+		},
+		{ // Braces protect corporate names.
+			from: "{Jane and John Consulting} and {The Tor Project}",
+			to:   "Jane and John Consulting, The Tor Project",
+		},
+	}
+
+	for _, test := range testCases {
+		to := DecodeAuthors(test.from)
+		if to != test.to {
+			t.Errorf("Expected\n%s\ngot\n%s", test.to, to)
+		}
+	}
+}
+
+func TestDecodeLaTeX(t *testing.T) {
+	testCases := []conversion{
+		{from: `Kurt G{\"o}del`, to: "Kurt Gödel"},
+		{from: `Kurt G\"{o}del`, to: "Kurt Gödel"},
+		{from: `Kurt G\"odel`, to: "Kurt Gödel"},
+		{from: `Fran\c{c}ois`, to: "François"},
+		{from: `Fran\c cois`, to: "François"},
+		{from: `Ji\v{r}{\'\i}`, to: "Jiří"},
+		{from: `Erd\H{o}s`, to: "Erdős"},
+		{from: `{\AA}ngstr{\"o}m`, to: "Ångström"},
+		{from: `Stra{\ss}e`, to: "Straße"},
+		{from: `{\O}ystein S{\o}rensen`, to: "Øystein Sørensen"},
+		{from: `Encyclop{\ae}dia`, to: "Encyclopædia"},
+		{from: `{\L}ukasz Mi{\l}osz`, to: "Łukasz Miłosz"},
+		{from: `\'{\i}`, to: "í"},
+		{from: `\~{g}`, to: "g̃"},
+		{from: `Tor---the Onion Router`, to: "Tor—the Onion Router"},
+		{from: `Pages 1--10`, to: "Pages 1–10"},
+		{from: `A\textendash{}B\textemdash C`, to: "A–B—C"},
+		{from: `Section~3`, to: "Section 3"},
+		{from: `\emph{Not} \textbf{so} \texttt{bad}`, to: "Not so bad"},
+		{from: `AT\&T, 100\%, \$5, \_`, to: "AT&T, 100%, $5, _"},
+		{from: `Client $\rightarrow$ Server`, to: "Client → Server"},
+		{from: `$\alpha$-$\beta$ and $x^2$, $H_2O$`, to: "α-β and x², H₂O"},
+		{from: `\ldots{} and \textquotedblleft more\textquotedblright`, to: "… and “more”"},
+		{from: `\unknown{Command}`, to: "unknownCommand"},
+	}
+
+	for _, test := range testCases {
+		to := decodeLaTeX(test.from)
+		if to != test.to {
+			t.Errorf("Expected\n%s\ngot\n%s", test.to, to)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package censorbib
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/nickng/bibtex"
+)
+
+// Titles that are at least this similar are most likely the same paper, e.g.
+// a workshop paper and its journal version.
+const titleSimilarityThreshold = 0.9
+
+// duplicateAllowlist maps pairs of cite names that look like duplicates but
+// are different papers, e.g. two parts of a series with almost the same title,
+// to the reason why.  The order of the cite names doesn't matter.
+var duplicateAllowlist = map[[2]string]string{}
+
+// duplicate is a pair of entries that are likely the same paper.  The first
+// entry is the one that comes first in the .bib file.
+type duplicate struct {
+	first      *bibtex.BibEntry
+	second     *bibtex.BibEntry
+	reason     string
+	similarity float64
+}
+
+func (d duplicate) String() string {
+	return fmt.Sprintf("possible duplicate of %s: %s (similarity %.2f)", d.first.CiteName, d.reason, d.similarity)
+}
+
+// findDuplicates returns the pairs of entries that are likely the same paper,
+// except for the pairs in the given allowlist.  Entries with the same cite
+// name are always reported.
+func findDuplicates(entries []*bibtex.BibEntry, allowlist map[[2]string]string) []duplicate {
+	titles := make([][]string, len(entries))
+	urls := make([]string, len(entries))
+	for i, entry := range entries {
+		titles[i] = titleTokens(toStr(entry.Fields["title"]))
+		urls[i] = normalizeURL(toStr(entry.Fields["url"]))
+	}
+
+	duplicates := []duplicate{}
+	for j := range entries {
+		for i := 0; i < j; i++ {
+			a, b := entries[i], entries[j]
+			d := duplicate{first: a, second: b, similarity: 1}
+			switch {
+			case a.CiteName == b.CiteName:
+				d.reason = "same cite name"
+			case isParent(a) || isParent(b):
+				continue // Proceedings are not papers.
+			case isAllowedDuplicate(allowlist, a.CiteName, b.CiteName):
+				continue
+			case urls[i] != "" && urls[i] == urls[j]:
+				d.reason = "same URL"
+			case len(titles[i]) > 0 && strings.Join(titles[i], " ") == strings.Join(titles[j], " "):
+				d.reason = "same title"
+			default:
+				d.similarity = titleSimilarity(titles[i], titles[j])
+				if d.similarity < titleSimilarityThreshold {
+					continue
+				}
+				d.reason = "similar title"
+			}
+			duplicates = append(duplicates, d)
+		}
+	}
+	return duplicates
+}
+
+func isAllowedDuplicate(allowlist map[[2]string]string, a, b string) bool {
+	_, ok := allowlist[[2]string{a, b}]
+	if !ok {
+		_, ok = allowlist[[2]string{b, a}]
+	}
+	return ok
+}
+
+// normalizeURL strips the parts of a URL that don't matter when comparing it
+// to another URL, so that e.g. http://www.example.com/paper.pdf and
+// https://example.com/paper.pdf are considered the same.
+func normalizeURL(url string) string {
+	url = strings.TrimSpace(url)
+	for _, prefix := range []string{"https://", "http://", "www."} {
+		url = strings.TrimPrefix(url, prefix)
+	}
+	return strings.TrimSuffix(url, "/")
+}
+
+// titleTokens normalizes the given title and splits it into lowercase words.
+func titleTokens(title string) []string {
+	return strings.FieldsFunc(strings.ToLower(DecodeTitle(title)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// titleSimilarity returns a score between 0 and 1 that tells us how similar
+// the two (tokenized) titles are.  The score is the larger of the titles'
+// Jaccard index and their normalized edit distance, so that we catch both
+// reordered words and small typos.
+func titleSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	tokens := make(map[string]int)
+	for _, t := range a {
+		tokens[t] |= 1
+	}
+	for _, t := range b {
+		tokens[t] |= 2
+	}
+	both := 0
+	for _, set := range tokens {
+		if set == 3 {
+			both++
+		}
+	}
+	jaccard := float64(both) / float64(len(tokens))
+	// Computing the edit distance is expensive, and titles with few words in
+	// common won't end up being similar enough anyway.
+	if jaccard < titleSimilarityThreshold/2 {
+		return jaccard
+	}
+
+	left, right := strings.Join(a, " "), strings.Join(b, " ")
+	longest := max(len([]rune(left)), len([]rune(right)))
+	ratio := 1 - float64(editDistance(left, right))/float64(longest)
+	return max(jaccard, ratio)
+}
@@ -0,0 +1,40 @@
+package censorbib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickng/bibtex"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	bib, err := bibtex.Parse(strings.NewReader(`
+@misc{Doe2024a, title = {Measuring Censorship in {China}}, url = {https://example.com/a.pdf}}
+@misc{Doe2024a, title = {Something else entirely}, url = {https://example.com/b.pdf}}
+@misc{Doe2024b, title = {A third paper}, url = {http://www.example.com/a.pdf}}
+@misc{Doe2025a, title = {Measuring censorship in China}, url = {https://example.com/c.pdf}}
+@misc{Doe2025b, title = {Measuring Censorhsip in {China}}, url = {https://example.com/d.pdf}}
+@misc{Roe2025a, title = {Evading Censorship in {Iran}}, url = {https://example.com/e.pdf}}
+@misc{Roe2025b, title = {Evading Censorship in {Iraq}}, url = {https://example.com/f.pdf}}
+`))
+	if err != nil {
+		t.Fatalf("failed to parse bibtex: %v", err)
+	}
+
+	var got []string
+	// An allowlisted pair isn't reported, no matter its order.
+	allowlist := map[[2]string]string{{"Roe2025b", "Roe2025a"}: "different countries"}
+	for _, d := range findDuplicates(bib.Entries, allowlist) {
+		got = append(got, d.second.CiteName+": "+d.String())
+	}
+	want := []string{
+		"Doe2024a: possible duplicate of Doe2024a: same cite name (similarity 1.00)",
+		"Doe2024b: possible duplicate of Doe2024a: same URL (similarity 1.00)",
+		"Doe2025a: possible duplicate of Doe2024a: same title (similarity 1.00)",
+		"Doe2025b: possible duplicate of Doe2024a: similar title (similarity 0.97)",
+		"Doe2025b: possible duplicate of Doe2025a: similar title (similarity 0.97)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected duplicates:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
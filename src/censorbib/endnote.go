@@ -0,0 +1,98 @@
+package censorbib
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// endNoteXML is EndNote's XML export format, which EndNote imports without
+// the guesswork that its BibTeX import needs.
+type endNoteXML struct {
+	XMLName xml.Name        `xml:"xml"`
+	Records []endNoteRecord `xml:"records>record"`
+}
+
+type endNoteRecord struct {
+	RefType    endNoteRefType `xml:"ref-type"`
+	Authors    []string       `xml:"contributors>authors>author"`
+	Title      string         `xml:"titles>title"`
+	Secondary  string         `xml:"titles>secondary-title,omitempty"`
+	Periodical string         `xml:"periodical>full-title,omitempty"`
+	Pages      string         `xml:"pages,omitempty"`
+	Volume     string         `xml:"volume,omitempty"`
+	Number     string         `xml:"number,omitempty"`
+	Year       string         `xml:"dates>year,omitempty"`
+	Publisher  string         `xml:"publisher,omitempty"`
+	Abstract   string         `xml:"abstract,omitempty"`
+	Notes      string         `xml:"notes,omitempty"`
+	URLs       []string       `xml:"urls>related-urls>url"`
+	PDFURLs    []string       `xml:"urls>pdf-urls>url"`
+	Label      string         `xml:"label"`
+}
+
+// endNoteRefType is EndNote's reference type, which is identified by its
+// number.  The name is only informative.
+type endNoteRefType struct {
+	Name   string `xml:"name,attr"`
+	Number int    `xml:",chardata"`
+}
+
+// The EndNote reference types of our entry types.
+var endNoteRefTypes = map[string]endNoteRefType{
+	"inproceedings": {"Conference Paper", 47},
+	"article":       {"Journal Article", 17},
+	"techreport":    {"Report", 27},
+	"misc":          {"Generic", 13},
+}
+
+// WriteEndNoteXML writes the bibliography in EndNote's XML format to w.  The
+// bibliography is sorted first.
+func WriteEndNoteXML(w io.Writer, bib *Bibliography) error {
+	bib.Sort()
+	records := []endNoteRecord{}
+	for _, entry := range bib.Entries() {
+		records = append(records, makeEndNoteRecord(&entry))
+	}
+	ew := &errWriter{w: w}
+	ew.print(xml.Header)
+	enc := xml.NewEncoder(ew)
+	enc.Indent("", "  ")
+	if err := enc.Encode(endNoteXML{Records: records}); err != nil {
+		return fmt.Errorf("failed to encode EndNote XML: %w", err)
+	}
+	ew.println()
+	return ew.err
+}
+
+func makeEndNoteRecord(entry *Entry) endNoteRecord {
+	view := entryView(entry)
+	record := endNoteRecord{
+		RefType:   endNoteRefTypes[entry.Type],
+		Title:     view.Title,
+		Pages:     entryField(entry, "pages"),
+		Year:      view.Year,
+		Publisher: entryInstitutionOrPublisher(entry),
+		Abstract:  entryField(entry, "abstract"),
+		Notes:     entryField(entry, "note"),
+		URLs:      []string{view.URL},
+		PDFURLs:   []string{view.CachedURL},
+		Label:     entry.CiteName,
+	}
+	switch entry.Type {
+	case "inproceedings":
+		record.Secondary = view.Venue
+	case "article":
+		record.Secondary = view.Venue
+		record.Periodical = view.Venue
+		record.Volume = entryField(entry, "volume")
+		record.Number = entryField(entry, "number")
+	case "techreport":
+		record.Number = entryField(entry, "number")
+	}
+	names, _ := entryNames(entry)
+	for _, name := range names {
+		record.Authors = append(record.Authors, name.Inverted())
+	}
+	return record
+}
@@ -0,0 +1,70 @@
+package censorbib
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nickng/bibtex"
+)
+
+func TestMakeEndNoteRecord(t *testing.T) {
+	entry := mustParse(t, `@techreport{Doe2024a,
+		author = {Jane Doe and {The Tor Project}},
+		title = {Report},
+		institution = {Example University},
+		number = {TR-42},
+		year = {2024},
+		url = {https://example.com/report.pdf},
+	}`)
+
+	want := endNoteRecord{
+		RefType:   endNoteRefType{"Report", 27},
+		Authors:   []string{"Doe, Jane", "The Tor Project"},
+		Title:     "Report",
+		Number:    "TR-42",
+		Year:      "2024",
+		Publisher: "Example University",
+		URLs:      []string{"https://example.com/report.pdf"},
+		PDFURLs:   []string{cachedURL("Doe2024a")},
+		Label:     "Doe2024a",
+	}
+	if got := makeEndNoteRecord(&entry); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected\n%+v\ngot\n%+v", want, got)
+	}
+
+	// Without an institution, the publisher is kept.
+	delete(entry.Fields, "institution")
+	entry.Fields["publisher"] = bibtex.NewBibConst("Example Press")
+	if got := makeEndNoteRecord(&entry).Publisher; got != "Example Press" {
+		t.Errorf("Expected publisher %q, got %q", "Example Press", got)
+	}
+}
+
+func TestWriteEndNoteXML(t *testing.T) {
+	bib, err := Load(strings.NewReader(feedBib))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := WriteEndNoteXML(buf, bib); err != nil {
+		t.Fatalf("failed to write EndNote XML: %v", err)
+	}
+	if !strings.Contains(buf.String(), `<ref-type name="Conference Paper">47</ref-type>`) {
+		t.Errorf("unexpected reference types in %s", buf)
+	}
+	var doc endNoteXML
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to parse EndNote XML: %v", err)
+	}
+	if len(doc.Records) != 3 {
+		t.Fatalf("expected 3 records but got %d", len(doc.Records))
+	}
+	for _, got := range doc.Records {
+		if got.Label == "Müller2024a" && (got.RefType.Number != 47 || got.Secondary != "Free and Open Communications on the Internet") {
+			t.Errorf("unexpected conference paper: %+v", got)
+		}
+	}
+}
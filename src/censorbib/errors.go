@@ -0,0 +1,198 @@
+package censorbib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nickng/bibtex"
+)
+
+// Error is a problem with a .bib file, along with where it occurred.  Line
+// and Column are 1-based, and zero if unknown.  CiteName and Field are empty
+// if the problem doesn't concern a specific entry or field.
+type Error struct {
+	Path     string
+	Line     int
+	Column   int
+	CiteName string
+	Field    string
+	Err      error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	if e.Path != "" {
+		b.WriteString(e.Path + ":")
+	}
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "%d:%d:", e.Line, e.Column)
+	}
+	if b.Len() > 0 {
+		b.WriteString(" ")
+	}
+	if e.CiteName != "" {
+		b.WriteString(e.CiteName + ": ")
+	}
+	if e.Field != "" {
+		b.WriteString(e.Field + ": ")
+	}
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// sortErrors sorts the given errors by their position in the file.
+func sortErrors(errs []*Error) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		a, b := errs[i], errs[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// joinErrors sorts the given errors by position and joins them into one,
+// or returns nil if there are none.
+func joinErrors(errs []*Error) error {
+	sortErrors(errs)
+	joined := []error{}
+	for _, err := range errs {
+		joined = append(joined, err)
+	}
+	return errors.Join(joined...)
+}
+
+// lineIndex maps byte offsets in a file to 1-based line and column numbers.
+type lineIndex struct {
+	path     string
+	contents []byte
+	starts   []int
+}
+
+func newLineIndex(path string, contents []byte) *lineIndex {
+	starts := []int{0}
+	for i, c := range contents {
+		if c == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return &lineIndex{path: path, contents: contents, starts: starts}
+}
+
+func (idx *lineIndex) position(offset int) (int, int) {
+	line := sort.Search(len(idx.starts), func(i int) bool {
+		return idx.starts[i] > offset
+	})
+	column := utf8.RuneCount(idx.contents[idx.starts[line-1]:offset]) + 1
+	return line, column
+}
+
+// errorf returns an *Error for the given byte offset in the file.
+func (idx *lineIndex) errorf(offset int, citeName, field, format string, a ...any) *Error {
+	line, column := idx.position(offset)
+	return &Error{
+		Path:     idx.path,
+		Line:     line,
+		Column:   column,
+		CiteName: citeName,
+		Field:    field,
+		Err:      fmt.Errorf(format, a...),
+	}
+}
+
+// parseError turns an error returned by the vendored parser for the given raw
+// entry into an *Error.  The parser's position is relative to the start of
+// the entry.
+func (idx *lineIndex) parseError(raw rawBibEntry, citeName string, err error) *Error {
+	var macroErr *undefinedMacroError
+	if errors.As(err, &macroErr) {
+		return idx.errorf(raw.offset+macroErr.ref.offset, citeName, macroErr.ref.field, "%w", err)
+	}
+	var parseErr *bibtex.ErrParse
+	if !errors.As(err, &parseErr) {
+		return idx.errorf(raw.offset, citeName, "", "%w", err)
+	}
+
+	offset := raw.offset
+	lines := strings.SplitAfter(raw.raw, "\n")
+	for i := 0; i < len(parseErr.Pos.Lines) && i < len(lines); i++ {
+		offset += len(lines[i])
+	}
+	bibErr := idx.errorf(offset, citeName, "", "%s", parseErr.Err)
+	if len(parseErr.Pos.Lines) == 0 {
+		bibErr.Column += parseErr.Pos.Char - 1
+	} else {
+		bibErr.Column = parseErr.Pos.Char
+	}
+	return bibErr
+}
+
+// parseBibTeX parses the given BibTeX using the vendored parser.
+func parseBibTeX(s string) (*bibtex.BibTex, error) {
+	bib, err := bibtex.Parse(strings.NewReader(s))
+	if err != nil {
+		// The vendored parser keeps global state that a syntax error leaves
+		// dirty.  Feeding it a lone closing brace resets that state, so the
+		// next call is parsed from a clean slate.
+		_, _ = bibtex.Parse(strings.NewReader("}"))
+		return nil, err
+	}
+	return bib, nil
+}
+
+// citeNameOffset returns the byte offset of the entry's cite name in the .bib
+// file.
+func citeNameOffset(raw rawBibEntry) int {
+	if loc := re.FindStringSubmatchIndex(raw.raw); loc != nil {
+		return raw.offset + loc[2]
+	}
+	return raw.offset
+}
+
+// fieldOffset returns the byte offset of the given field's name in the .bib
+// file, or the offset of the entry if the field cannot be found.
+func fieldOffset(raw rawBibEntry, field string) int {
+	re := regexp.MustCompile(`(?m)^[ \t]*(` + regexp.QuoteMeta(field) + `)[ \t]*=`)
+	if loc := re.FindStringSubmatchIndex(raw.raw); loc != nil {
+		return raw.offset + loc[2]
+	}
+	return raw.offset
+}
+
+// errWriter wraps an io.Writer and remembers the first error that occurred
+// while writing to it.  Once an error occurred, all further writes are
+// no-ops.  That way, we only need to check for errors once we're done.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
+}
+
+func (ew *errWriter) print(a ...any) {
+	_, _ = fmt.Fprint(ew, a...)
+}
+
+func (ew *errWriter) println(a ...any) {
+	_, _ = fmt.Fprintln(ew, a...)
+}
+
+func (ew *errWriter) printf(format string, a ...any) {
+	_, _ = fmt.Fprintf(ew, format, a...)
+}
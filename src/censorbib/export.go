@@ -0,0 +1,207 @@
+package censorbib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// The version of censorbib.json's format.  Bump it whenever a change to the
+// format could break consumers, e.g. when removing or renaming a property,
+// and update jsonExportSchema.
+const jsonExportVersion = 1
+
+// jsonExport is censorbib.json: the whole bibliography, for tools and mirrors
+// that don't want to scrape our HTML.
+type jsonExport struct {
+	Schema  string      `json:"$schema"`
+	Version int         `json:"version"`
+	Updated string      `json:"updated,omitempty"`
+	Entries []jsonEntry `json:"entries"`
+}
+
+type jsonEntry struct {
+	CiteName  string               `json:"citeName"`
+	Type      string               `json:"type"`
+	Title     string               `json:"title"`
+	Authors   []jsonName           `json:"authors"`
+	Venue     string               `json:"venue,omitempty"`
+	Year      string               `json:"year"`
+	URL       string               `json:"url"`
+	CachedURL string               `json:"cachedUrl"`
+	PageURL   string               `json:"pageUrl"`
+	Added     string               `json:"added,omitempty"`
+	Fields    map[string]jsonField `json:"fields"`
+	RawBibtex string               `json:"rawBibtex"`
+	// The paper as Schema.org JSON-LD, as its page embeds it.
+	LinkedData linkedDataArticle `json:"linkedData"`
+}
+
+// jsonName is an author, with the parts of the name decoded.
+type jsonName struct {
+	Name   string `json:"name"`
+	First  string `json:"first,omitempty"`
+	Von    string `json:"von,omitempty"`
+	Last   string `json:"last"`
+	Jr     string `json:"jr,omitempty"`
+	Others bool   `json:"others,omitempty"`
+}
+
+// jsonField is a field's value as LaTeX, with string macros and crossrefs
+// resolved, and decoded into plain Unicode text.
+type jsonField struct {
+	Raw     string `json:"raw"`
+	Decoded string `json:"decoded"`
+}
+
+// WriteJSON writes the bibliography as censorbib.json to w.  The bibliography
+// is sorted first.
+func WriteJSON(w io.Writer, bib *Bibliography) error {
+	bib.Sort()
+	export := jsonExport{
+		Schema:  siteURL + "censorbib.schema.json",
+		Version: jsonExportVersion,
+		Entries: []jsonEntry{},
+	}
+	if !bib.updated.IsZero() {
+		export.Updated = bib.updated.UTC().Format(time.DateOnly)
+	}
+	for _, entry := range bib.Entries() {
+		export.Entries = append(export.Entries, makeJSONEntry(&entry))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(export); err != nil {
+		return fmt.Errorf("failed to encode bibliography: %w", err)
+	}
+	return nil
+}
+
+func makeJSONEntry(entry *Entry) jsonEntry {
+	view := entryView(entry)
+	e := jsonEntry{
+		CiteName:   entry.CiteName,
+		Type:       entry.Type,
+		Title:      view.Title,
+		Authors:    []jsonName{},
+		Venue:      view.Venue,
+		Year:       view.Year,
+		URL:        view.URL,
+		CachedURL:  view.CachedURL,
+		PageURL:    paperURL(entry.CiteName),
+		Added:      formatAdded(entry.Added),
+		Fields:     make(map[string]jsonField),
+		RawBibtex:  entry.RawBibtex,
+		LinkedData: linkedDataArticle{schemaOrgContext, makeScholarlyArticle(entry)},
+	}
+	if names, err := ParseAuthors(toStr(entry.Fields["author"])); err == nil {
+		for _, name := range names {
+			e.Authors = append(e.Authors, jsonName{
+				Name:   name.String(),
+				First:  decodeLaTeX(name.First),
+				Von:    decodeLaTeX(name.Von),
+				Last:   decodeLaTeX(name.Last),
+				Jr:     decodeLaTeX(name.Jr),
+				Others: name.isOthers(),
+			})
+		}
+	}
+	for name, value := range entry.Fields {
+		raw := toStr(value)
+		decoded := decodeLaTeX(raw)
+		if name == "author" {
+			decoded = DecodeAuthors(raw)
+		}
+		e.Fields[name] = jsonField{Raw: raw, Decoded: decoded}
+	}
+	return e
+}
+
+// WriteJSONSchema writes the JSON Schema of censorbib.json to w.
+func WriteJSONSchema(w io.Writer, _ *Bibliography) error {
+	_, err := io.WriteString(w, jsonExportSchema)
+	return err
+}
+
+const jsonExportSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "https://censorbib.nymity.ch/censorbib.schema.json",
+  "title": "CensorBib",
+  "description": "The papers in the Internet censorship bibliography.",
+  "type": "object",
+  "required": ["version", "entries"],
+  "properties": {
+    "$schema": {"type": "string", "format": "uri"},
+    "version": {
+      "description": "The version of this format.  It changes whenever a change could break consumers.",
+      "const": 1
+    },
+    "updated": {
+      "description": "When the bibliography was last updated.",
+      "type": "string",
+      "format": "date"
+    },
+    "entries": {
+      "description": "The papers, newest first.",
+      "type": "array",
+      "items": {"$ref": "#/$defs/entry"}
+    }
+  },
+  "$defs": {
+    "entry": {
+      "type": "object",
+      "required": ["citeName", "type", "title", "authors", "year", "url", "cachedUrl", "pageUrl", "fields", "rawBibtex", "linkedData"],
+      "properties": {
+        "citeName": {"description": "The BibTeX cite name, e.g. Doe2024a.", "type": "string"},
+        "type": {"description": "The BibTeX entry type.", "enum": ["article", "inproceedings", "techreport", "misc"]},
+        "title": {"description": "The decoded title.", "type": "string"},
+        "authors": {"type": "array", "items": {"$ref": "#/$defs/name"}},
+        "venue": {"description": "The decoded booktitle or journal.", "type": "string"},
+        "year": {"type": "string"},
+        "url": {"description": "Where the paper is published.", "type": "string", "format": "uri"},
+        "cachedUrl": {"description": "Our copy of the paper's PDF.", "type": "string", "format": "uri"},
+        "pageUrl": {"description": "The paper's page on CensorBib.", "type": "string", "format": "uri"},
+        "added": {"description": "When the paper was added to CensorBib.", "type": "string", "format": "date-time"},
+        "fields": {
+          "description": "All BibTeX fields by name, including inherited ones.",
+          "type": "object",
+          "additionalProperties": {"$ref": "#/$defs/field"}
+        },
+        "rawBibtex": {"description": "The entry as it appears in references.bib.", "type": "string"},
+        "linkedData": {
+          "description": "The paper as a Schema.org ScholarlyArticle in JSON-LD, as its page embeds it.",
+          "type": "object",
+          "required": ["@context", "@type"],
+          "properties": {
+            "@context": {"const": "https://schema.org"},
+            "@type": {"const": "ScholarlyArticle"}
+          }
+        }
+      }
+    },
+    "name": {
+      "description": "An author's name, split into BibTeX's four parts.",
+      "type": "object",
+      "required": ["name", "last"],
+      "properties": {
+        "name": {"description": "The full name, for display.", "type": "string"},
+        "first": {"type": "string"},
+        "von": {"type": "string"},
+        "last": {"type": "string"},
+        "jr": {"type": "string"},
+        "others": {"description": "Whether this stands for further, unnamed authors.", "type": "boolean"}
+      }
+    },
+    "field": {
+      "type": "object",
+      "required": ["raw", "decoded"],
+      "properties": {
+        "raw": {"description": "The value as LaTeX, with string macros expanded.", "type": "string"},
+        "decoded": {"description": "The value as plain Unicode text.", "type": "string"}
+      }
+    }
+  }
+}
+`
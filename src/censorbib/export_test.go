@@ -0,0 +1,85 @@
+package censorbib
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteJSON(t *testing.T) {
+	bib, err := Load(strings.NewReader(`@string{foci = {Free and Open Communications on the Internet}}
+
+@inproceedings{Doe2024a,
+	author = {Jane Doe and Ludwig van Beethoven and others},
+	title = {Exported {Paper}},
+	booktitle = foci,
+	publisher = {Example Publisher},
+	year = {2024},
+	pages = {1--10},
+	url = {https://example.com/paper.pdf},
+	discussion_url = {https://example.com/discussion},
+}`))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+	bib.SetUpdated(time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC))
+	buf := new(bytes.Buffer)
+	if err := WriteJSON(buf, bib); err != nil {
+		t.Fatalf("failed to write JSON: %v", err)
+	}
+
+	var got jsonExport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+	if got.Version != jsonExportVersion || got.Updated != "2024-03-14" || len(got.Entries) != 1 {
+		t.Fatalf("unexpected export: %+v", got)
+	}
+	entry := got.Entries[0]
+	if entry.Title != "Exported Paper" || entry.Venue != "Free and Open Communications on the Internet" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if entry.CachedURL != cachedURL("Doe2024a") || entry.PageURL != paperURL("Doe2024a") {
+		t.Errorf("unexpected URLs: %+v", entry)
+	}
+	if ld := entry.LinkedData; ld.Context != schemaOrgContext || ld.Type != "ScholarlyArticle" || ld.ID != paperURL("Doe2024a") {
+		t.Errorf("unexpected linked data: %+v", ld)
+	}
+	wantAuthors := []jsonName{
+		{Name: "Jane Doe", First: "Jane", Last: "Doe"},
+		{Name: "Ludwig van Beethoven", First: "Ludwig", Von: "van", Last: "Beethoven"},
+		{Name: "et al.", Last: "others", Others: true},
+	}
+	if len(entry.Authors) != len(wantAuthors) {
+		t.Fatalf("expected %d authors but got %+v", len(wantAuthors), entry.Authors)
+	}
+	for i, want := range wantAuthors {
+		if entry.Authors[i] != want {
+			t.Errorf("expected author %+v but got %+v", want, entry.Authors[i])
+		}
+	}
+	for name, want := range map[string]jsonField{
+		"title":          {Raw: "Exported {Paper}", Decoded: "Exported Paper"},
+		"booktitle":      {Raw: "Free and Open Communications on the Internet", Decoded: "Free and Open Communications on the Internet"},
+		"pages":          {Raw: "1--10", Decoded: "1–10"},
+		"discussion_url": {Raw: "https://example.com/discussion", Decoded: "https://example.com/discussion"},
+		"author":         {Raw: "Jane Doe and Ludwig van Beethoven and others", Decoded: "Jane Doe, Ludwig van Beethoven, et al."},
+	} {
+		if entry.Fields[name] != want {
+			t.Errorf("%s: expected %+v but got %+v", name, want, entry.Fields[name])
+		}
+	}
+}
+
+func TestJSONExportSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(jsonExportSchema), &schema); err != nil {
+		t.Fatalf("schema isn't valid JSON: %v", err)
+	}
+	version := schema["properties"].(map[string]any)["version"].(map[string]any)["const"]
+	if version != float64(jsonExportVersion) {
+		t.Errorf("schema is for version %v, but the export is version %d", version, jsonExportVersion)
+	}
+}
@@ -0,0 +1,224 @@
+package censorbib
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// The number of papers that our feeds list.
+const feedSize = 50
+
+const feedTitle = "CensorBib: Recently added papers"
+
+// atomFeed is an Atom feed, as specified in RFC 4287.
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title     string       `xml:"title"`
+	ID        string       `xml:"id"`
+	Published string       `xml:"published"`
+	Updated   string       `xml:"updated"`
+	Authors   []atomPerson `xml:"author"`
+	Links     []atomLink   `xml:"link"`
+	Summary   string       `xml:"summary"`
+	Content   string       `xml:"content,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+// rssFeed is an RSS 2.0 feed.  Authors go into Dublin Core's creator
+// element, because RSS's own author element expects an email address.
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string       `xml:"title"`
+	Link        string       `xml:"link"`
+	GUID        rssGUID      `xml:"guid"`
+	PubDate     string       `xml:"pubDate"`
+	Creators    []string     `xml:"dc:creator"`
+	Description string       `xml:"description"`
+	Enclosure   rssEnclosure `xml:"enclosure"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// rssEnclosure is a file attached to an item.  RSS requires its length, which
+// we don't know, so we set it to 0, as is customary.
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// entryURL returns the URL of the given entry on the main page.
+func entryURL(citeName string) string {
+	return siteURL + "#" + url.PathEscape(citeName)
+}
+
+// recentEntries returns the feedSize entries that were added last, most
+// recent first.  Entries whose date added we don't know are left out.
+func recentEntries(bibEntries []Entry) []Entry {
+	recent := []Entry{}
+	for _, entry := range bibEntries {
+		if !entry.Added.IsZero() {
+			recent = append(recent, entry)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		if !recent[i].Added.Equal(recent[j].Added) {
+			return recent[i].Added.After(recent[j].Added)
+		}
+		return recent[i].CiteName < recent[j].CiteName
+	})
+	if len(recent) > feedSize {
+		recent = recent[:feedSize]
+	}
+	return recent
+}
+
+// feedItem is what both of our feeds say about an entry.
+type feedItem struct {
+	view        bibEntryView
+	authors     []string
+	added       time.Time
+	description string
+	abstract    string
+	pdfURL      string
+}
+
+func makeFeedItem(entry *Entry) feedItem {
+	view := entryView(entry)
+	item := feedItem{
+		view:        view,
+		added:       entry.Added,
+		description: paperDescription(view),
+		abstract:    entryField(entry, "abstract"),
+		pdfURL:      pdfURL(view),
+	}
+	names, _ := entryNames(entry)
+	for _, name := range names {
+		item.authors = append(item.authors, name.String())
+	}
+	return item
+}
+
+// WriteAtom writes an Atom feed of the papers that were added last to w.
+func WriteAtom(w io.Writer, bib *Bibliography) error {
+	feed := atomFeed{
+		Title: feedTitle,
+		ID:    siteURL + "feed.atom",
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: siteURL + "feed.atom"},
+			{Rel: "alternate", Type: "text/html", Href: siteURL},
+		},
+	}
+	var updated time.Time
+	for _, entry := range recentEntries(bib.Entries()) {
+		item := makeFeedItem(&entry)
+		if item.added.After(updated) {
+			updated = item.added
+		}
+		e := atomEntry{
+			Title:     item.view.Title,
+			ID:        entryURL(entry.CiteName),
+			Published: item.added.Format(time.RFC3339),
+			Updated:   item.added.Format(time.RFC3339),
+			Links: []atomLink{
+				{Rel: "alternate", Type: "text/html", Href: entryURL(entry.CiteName)},
+				{Rel: "enclosure", Type: "application/pdf", Href: item.pdfURL},
+			},
+			Summary: item.description,
+			Content: item.abstract,
+		}
+		for _, author := range item.authors {
+			e.Authors = append(e.Authors, atomPerson{author})
+		}
+		feed.Entries = append(feed.Entries, e)
+	}
+	feed.Updated = updated.Format(time.RFC3339)
+	return writeXML(w, feed)
+}
+
+// WriteRSS writes an RSS feed of the papers that were added last to w.
+func WriteRSS(w io.Writer, bib *Bibliography) error {
+	feed := rssFeed{
+		Version: "2.0",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       feedTitle,
+			Link:        siteURL,
+			Description: "Selected research papers in Internet censorship",
+		},
+	}
+	var updated time.Time
+	for _, entry := range recentEntries(bib.Entries()) {
+		item := makeFeedItem(&entry)
+		if item.added.After(updated) {
+			updated = item.added
+		}
+		description := item.description
+		if item.abstract != "" {
+			description += "\n\n" + item.abstract
+		}
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       item.view.Title,
+			Link:        entryURL(entry.CiteName),
+			GUID:        rssGUID{IsPermaLink: true, Value: entryURL(entry.CiteName)},
+			PubDate:     item.added.Format(time.RFC1123Z),
+			Creators:    item.authors,
+			Description: description,
+			Enclosure:   rssEnclosure{URL: item.pdfURL, Type: "application/pdf"},
+		})
+	}
+	if !updated.IsZero() {
+		feed.Channel.LastBuildDate = updated.Format(time.RFC1123Z)
+	}
+	return writeXML(w, feed)
+}
+
+func writeXML(w io.Writer, v any) error {
+	ew := &errWriter{w: w}
+	ew.print(xml.Header)
+	enc := xml.NewEncoder(ew)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode feed: %w", err)
+	}
+	ew.println()
+	return ew.err
+}
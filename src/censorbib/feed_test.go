@@ -0,0 +1,113 @@
+package censorbib
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+const feedBib = `@misc{Doe2024a,
+	author = {Jane Doe},
+	title = {Old Paper},
+	year = {2024},
+	url = {https://example.com/old.pdf},
+	added = {2024-01-02},
+}
+
+@inproceedings{Müller2024a,
+	author = {Max M{\"u}ller and John Doe},
+	title = {New Paper},
+	booktitle = {Free and Open Communications on the Internet},
+	year = {2024},
+	url = {https://example.com/new},
+	added = {2024-06-01},
+	abstract = {We study censorship.},
+}
+
+@misc{Doe2024b,
+	author = {Jane Doe},
+	title = {Paper of unknown age},
+	year = {2024},
+	url = {https://example.com/unknown.pdf},
+}
+`
+
+func TestWriteAtom(t *testing.T) {
+	bib, err := Load(strings.NewReader(feedBib))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := WriteAtom(buf, bib); err != nil {
+		t.Fatalf("failed to write feed: %v", err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatalf("failed to parse feed: %v\n%s", err, buf)
+	}
+	if feed.Updated != "2024-06-01T00:00:00Z" {
+		t.Errorf("unexpected feed update time %q", feed.Updated)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("expected 2 entries but got %d", len(feed.Entries))
+	}
+	newest := feed.Entries[0]
+	if newest.Title != "New Paper" || newest.ID != "https://censorbib.nymity.ch/#M%C3%BCller2024a" {
+		t.Errorf("unexpected newest entry: %+v", newest)
+	}
+	if len(newest.Authors) != 2 || newest.Authors[0].Name != "Max Müller" {
+		t.Errorf("unexpected authors: %+v", newest.Authors)
+	}
+	if newest.Content != "We study censorship." {
+		t.Errorf("unexpected content %q", newest.Content)
+	}
+	// The paper's URL doesn't point to a PDF, so the enclosure is our copy.
+	if got := newest.Links[1]; got.Rel != "enclosure" || got.Href != cachedURL("Müller2024a") {
+		t.Errorf("unexpected enclosure: %+v", got)
+	}
+	if got := feed.Entries[1].Links[1].Href; got != "https://example.com/old.pdf" {
+		t.Errorf("unexpected enclosure %q", got)
+	}
+}
+
+func TestWriteRSS(t *testing.T) {
+	bib, err := Load(strings.NewReader(feedBib))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := WriteRSS(buf, bib); err != nil {
+		t.Fatalf("failed to write feed: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">`,
+		`<lastBuildDate>Sat, 01 Jun 2024 00:00:00 +0000</lastBuildDate>`,
+		`<dc:creator>Max Müller</dc:creator>`,
+		`<pubDate>Tue, 02 Jan 2024 00:00:00 +0000</pubDate>`,
+		`<enclosure url="https://example.com/old.pdf" length="0" type="application/pdf"></enclosure>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("feed missing %q in %s", want, got)
+		}
+	}
+	if strings.Contains(got, "Paper of unknown age") {
+		t.Errorf("feed lists entry without date added")
+	}
+}
+
+func TestInvalidAdded(t *testing.T) {
+	_, err := parseBib("test.bib", []byte(`@misc{Doe2024a,
+	author = {Jane Doe},
+	title = {Paper},
+	year = {2024},
+	url = {https://example.com/paper.pdf},
+	added = {14.03.2024},
+}`))
+	want := `test.bib:6:2: Doe2024a: added: invalid date "14.03.2024"; expected YYYY-MM-DD`
+	if err == nil || err.Error() != want {
+		t.Fatalf("Expected\n%s\ngot\n%v", want, err)
+	}
+}
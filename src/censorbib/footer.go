@@ -0,0 +1,541 @@
+package censorbib
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+)
+
+// footerTemplate is the page's footer, with the BibTeX modal.  On the site,
+// the modal also offers the formats that it fetches from the site's exports.
+const footerTemplate = `<div id="bibtex-modal" hidden>
+  <div id="bibtex-backdrop" data-close-bibtex></div>
+  <section id="bibtex-dialog" role="dialog" aria-modal="true" aria-labelledby="bibtex-title">
+    <header>
+      <h2 id="bibtex-title">BibTeX</h2>
+      {{- if .Site}}
+      <select id="bibtex-format" aria-label="Format">
+        <option value="bibtex">BibTeX</option>
+        <option value="ris">RIS</option>
+        <option value="endNote">EndNote XML</option>
+      </select>
+      {{- end}}
+      <span id="bibtex-copy-status" aria-live="polite"></span>
+      <button id="bibtex-copy" type="button">Copy</button>
+      {{- if .Site}}
+      <button id="csl-copy" type="button">Copy CSL-JSON</button>
+      {{- end}}
+      <button id="bibtex-download" type="button">Download</button>
+      <button id="bibtex-close" type="button" data-close-bibtex>Close</button>
+    </header>
+    <pre id="bibtex-content"></pre>
+    {{- if .Site}}
+    <footer id="citation-box">
+      <select id="citation-style" aria-label="Citation style">
+        <option value="ieee">IEEE</option>
+        <option value="acm">ACM</option>
+        <option value="apa">APA</option>
+        <option value="usenix">USENIX</option>
+      </select>
+      <p id="citation-text"></p>
+      <button id="citation-copy" type="button">Copy citation</button>
+    </footer>
+    {{- end}}
+  </section>
+</div>
+
+<div id="footer">
+Icons taken without modification from
+<a href="https://fontawesome.com/license">Font Awesome</a>.
+</div>
+
+`
+
+// footerScript drives the search and the BibTeX modal.  It isn't part of
+// footerTemplate because html/template would strip its comments.
+const footerScript = `<script>
+(function () {
+  const dataElement = document.getElementById("reference-data");
+  const references = dataElement ? JSON.parse(dataElement.textContent) : [];
+  const referencesByCiteName = new Map(references.map((reference) => [reference.citeName, reference]));
+  const form = document.getElementById("search-form");
+  const input = document.getElementById("search-input");
+  const resultCount = document.getElementById("result-count");
+  const noResults = document.getElementById("no-results");
+  const modal = document.getElementById("bibtex-modal");
+  const modalTitle = document.getElementById("bibtex-title");
+  const modalContent = document.getElementById("bibtex-content");
+  const copyButton = document.getElementById("bibtex-copy");
+  const cslCopyButton = document.getElementById("csl-copy");
+  const downloadButton = document.getElementById("bibtex-download");
+  const formatSelect = document.getElementById("bibtex-format");
+  const citationStyle = document.getElementById("citation-style");
+  const citationText = document.getElementById("citation-text");
+  const citationCopyButton = document.getElementById("citation-copy");
+  const copyStatus = document.getElementById("bibtex-copy-status");
+  const closeButton = document.getElementById("bibtex-close");
+  const newOnlyToggle = document.getElementById("new-only-toggle");
+  const newOnly = document.getElementById("new-only");
+
+  function normalize(value) {
+    return String(value || "")
+      .toLocaleLowerCase("en")
+      .normalize("NFD")
+      .replace(/[\u0300-\u036f]/g, "")
+      .replace(/[^\p{L}\p{N}]+/gu, " ")
+      .trim();
+  }
+
+  function tokenize(value) {
+    const normalized = normalize(value);
+    return normalized === "" ? [] : normalized.split(/\s+/);
+  }
+
+  // Entries are new if they were added recently, or since the visitor's
+  // last visit, which we remember in localStorage.  The visit ends when the
+  // page is hidden, but we keep the previous visit in sessionStorage, so
+  // that reloading the page doesn't clear the badges.  Tabs that are opened
+  // during the visit see the same previous visit.
+  const lastVisitKey = "censorbib-last-visit";
+  let lastVisit = NaN;
+  try {
+    let previous = window.sessionStorage.getItem(lastVisitKey);
+    if (previous === null) {
+      previous = window.localStorage.getItem(lastVisitKey) || "";
+      window.sessionStorage.setItem(lastVisitKey, previous);
+    }
+    lastVisit = Date.parse(previous);
+    window.addEventListener("pagehide", () => {
+      try {
+        window.localStorage.setItem(lastVisitKey, new Date().toISOString());
+      } catch (error) {
+        // Storage may have been disabled in the meantime.
+      }
+    });
+  } catch (error) {
+    // Storage is unavailable, e.g. because cookies are disabled.
+  }
+
+  function markNew(item) {
+    if (!item) {
+      return false;
+    }
+    if (item.querySelector(".new-badge")) {
+      return true;
+    }
+    const added = Date.parse(item.dataset.added);
+    if (Number.isNaN(added) || Number.isNaN(lastVisit) || added <= lastVisit) {
+      return false;
+    }
+    const badge = document.createElement("span");
+    badge.className = "new-badge";
+    badge.title = "Added since your last visit";
+    badge.textContent = "New";
+    item.querySelector(".paper").after(" ", badge);
+    return true;
+  }
+
+  for (const reference of references) {
+    reference.searchTokens = tokenize([
+      reference.citeName,
+      reference.title,
+      reference.authors,
+      reference.venue,
+      reference.year,
+      reference.publisher,
+    ].join(" "));
+    reference.item = document.getElementById(reference.citeName);
+    reference.isNew = markNew(reference.item);
+    reference.originalHTML = reference.item ? reference.item.innerHTML : "";
+  }
+
+  function referenceMatches(reference, queryTokens) {
+    return queryTokens.every((queryToken) =>
+      reference.searchTokens.some((token) => token.startsWith(queryToken) || token.includes(queryToken))
+    );
+  }
+
+  function pluralize(count, singular, plural) {
+    return count === 1 ? singular : plural;
+  }
+
+  function updateURL(query) {
+    const params = new URLSearchParams(window.location.search);
+    if (query === "") {
+      params.delete("q");
+    } else {
+      params.set("q", query);
+    }
+    const search = params.toString();
+    const nextURL = window.location.pathname + (search ? "?" + search : "") + window.location.hash;
+    window.history.replaceState({}, "", nextURL);
+  }
+
+  function tokenizeForHighlight(value) {
+    return Array.from(new Set(
+      String(value || "")
+        .toLocaleLowerCase("en")
+        .split(/[^\p{L}\p{N}]+/gu)
+        .filter(Boolean)
+    )).sort((left, right) => right.length - left.length);
+  }
+
+  function nextMatch(text, lowerText, start, highlightTokens) {
+    let matchIndex = -1;
+    let matchToken = "";
+
+    for (const token of highlightTokens) {
+      const index = lowerText.indexOf(token, start);
+      if (index === -1) {
+        continue;
+      }
+      if (matchIndex === -1 || index < matchIndex || (index === matchIndex && token.length > matchToken.length)) {
+        matchIndex = index;
+        matchToken = token;
+      }
+    }
+
+    return { index: matchIndex, token: matchToken };
+  }
+
+  function highlightTextNode(textNode, highlightTokens) {
+    const text = textNode.nodeValue;
+    const lowerText = text.toLocaleLowerCase("en");
+    const fragment = document.createDocumentFragment();
+    let index = 0;
+
+    while (index < text.length) {
+      const match = nextMatch(text, lowerText, index, highlightTokens);
+      if (match.index === -1) {
+        fragment.append(document.createTextNode(text.slice(index)));
+        break;
+      }
+
+      if (match.index > index) {
+        fragment.append(document.createTextNode(text.slice(index, match.index)));
+      }
+
+      const mark = document.createElement("mark");
+      mark.textContent = text.slice(match.index, match.index + match.token.length);
+      fragment.append(mark);
+      index = match.index + match.token.length;
+    }
+
+    textNode.replaceWith(fragment);
+  }
+
+  function highlightMatches(item, highlightTokens) {
+    if (highlightTokens.length === 0) {
+      return;
+    }
+
+    const walker = document.createTreeWalker(item, NodeFilter.SHOW_TEXT, {
+      acceptNode(node) {
+        if (!node.nodeValue.trim() || node.parentElement.closest(".icons")) {
+          return NodeFilter.FILTER_REJECT;
+        }
+        return NodeFilter.FILTER_ACCEPT;
+      },
+    });
+    const textNodes = [];
+    while (walker.nextNode()) {
+      textNodes.push(walker.currentNode);
+    }
+    for (const textNode of textNodes) {
+      highlightTextNode(textNode, highlightTokens);
+    }
+  }
+
+  function applySearch(query, shouldUpdateURL) {
+    const queryTokens = tokenize(query);
+    const highlightTokens = tokenizeForHighlight(query);
+    let visibleCount = 0;
+
+    for (const reference of references) {
+      const item = reference.item;
+      if (!item) {
+        continue;
+      }
+      item.innerHTML = reference.originalHTML;
+      const visible = (queryTokens.length === 0 || referenceMatches(reference, queryTokens)) &&
+        (!newOnly.checked || reference.isNew);
+      item.hidden = !visible;
+      if (visible) {
+        highlightMatches(item, highlightTokens);
+        visibleCount++;
+      }
+    }
+
+    for (const group of document.querySelectorAll(".year-group")) {
+      group.hidden = !group.querySelector("li:not([hidden])");
+    }
+
+    resultCount.textContent = visibleCount + " " + pluralize(visibleCount, "paper", "papers");
+    noResults.hidden = visibleCount !== 0;
+    if (shouldUpdateURL) {
+      updateURL(query.trim());
+    }
+  }
+
+  input.addEventListener("input", () => {
+    applySearch(input.value, true);
+  });
+
+  newOnlyToggle.hidden = !references.some((reference) => reference.isNew);
+  newOnly.addEventListener("change", () => {
+    applySearch(input.value, false);
+  });
+
+  form.addEventListener("submit", (event) => {
+    event.preventDefault();
+    applySearch(input.value, true);
+  });
+
+  let modalReference = null;
+
+  // The RIS, EndNote XML, CSL-JSON, and citations of single entries are
+  // taken from the site's exports of the whole bibliography, which we only
+  // fetch once needed.
+  const exportRecords = new Map();
+
+  function fetchRecords(path, split) {
+    if (!exportRecords.has(path)) {
+      const records = fetch(path)
+        .then((response) => {
+          if (!response.ok) {
+            throw new Error(response.statusText);
+          }
+          return response.text();
+        })
+        .then(split);
+      // Try again next time if fetching failed.
+      records.catch(() => exportRecords.delete(path));
+      exportRecords.set(path, records);
+    }
+    return exportRecords.get(path);
+  }
+
+  function splitRIS(text) {
+    const records = new Map();
+    for (const record of text.match(/^TY {2}- [\s\S]*?^ER {2}- \r\n/gm) || []) {
+      const id = /^ID {2}- ([^\r\n]*)/m.exec(record);
+      if (id) {
+        records.set(id[1], record);
+      }
+    }
+    return records;
+  }
+
+  function splitEndNote(text) {
+    const records = new Map();
+    const doc = new DOMParser().parseFromString(text, "application/xml");
+    const serializer = new XMLSerializer();
+    for (const record of doc.querySelectorAll("record")) {
+      const label = record.querySelector("label");
+      if (label) {
+        records.set(label.textContent, '<?xml version="1.0" encoding="UTF-8"?>\n<xml>\n  <records>\n    ' +
+          serializer.serializeToString(record) + "\n  </records>\n</xml>\n");
+      }
+    }
+    return records;
+  }
+
+  function splitCSL(text) {
+    return new Map(JSON.parse(text).map((item) => [item.id, item]));
+  }
+
+  function splitCitations(text) {
+    return new Map(Object.entries(JSON.parse(text)));
+  }
+
+  function fetchCSL(citeName) {
+    return fetchRecords("censorbib.csl.json", splitCSL).then((items) => items.get(citeName));
+  }
+
+  // The formats that the modal can show an entry in.  Their text is either a
+  // string or a promise of one.
+  const formats = {
+    bibtex: { text: (reference) => reference.rawBibtex, extension: ".bib", type: "application/x-bibtex" },
+    ris: {
+      text: (reference) => fetchRecords("censorbib.ris", splitRIS).then((records) => records.get(reference.citeName)),
+      source: "censorbib.ris",
+      extension: ".ris",
+      type: "application/x-research-info-systems",
+    },
+    endNote: {
+      text: (reference) => fetchRecords("censorbib.xml", splitEndNote).then((records) => records.get(reference.citeName)),
+      source: "censorbib.xml",
+      extension: ".xml",
+      type: "application/xml",
+    },
+  };
+
+  // Only the site offers formats other than BibTeX.
+  function selectedFormat() {
+    return formatSelect ? formatSelect.value : "bibtex";
+  }
+
+  async function showFormat() {
+    const reference = modalReference;
+    const format = formats[selectedFormat()];
+    if (!reference) {
+      return;
+    }
+    copyButton.disabled = downloadButton.disabled = true;
+    modalContent.textContent = "Loading…";
+    const text = await Promise.resolve(format.text(reference)).catch(() => undefined);
+    if (reference !== modalReference || format !== formats[selectedFormat()]) {
+      return; // The visitor picked another entry or format in the meantime.
+    }
+    if (text === undefined) {
+      modalContent.textContent = "Failed to load " + format.source + ".";
+      return;
+    }
+    modalContent.textContent = text;
+    copyButton.disabled = downloadButton.disabled = false;
+  }
+
+  async function showCitation() {
+    const reference = modalReference;
+    const style = citationStyle.value;
+    if (!reference) {
+      return;
+    }
+    citationCopyButton.disabled = true;
+    citationText.textContent = "Loading…";
+    const citations = await fetchRecords("censorbib.citations.json", splitCitations)
+      .then((records) => records.get(reference.citeName))
+      .catch(() => undefined);
+    if (reference !== modalReference || style !== citationStyle.value) {
+      return; // The visitor picked another entry or style in the meantime.
+    }
+    if (citations === undefined) {
+      citationText.textContent = "Failed to load censorbib.citations.json.";
+      return;
+    }
+    citationText.textContent = citations[style];
+    citationCopyButton.disabled = false;
+  }
+
+  function openBibtex(citeName) {
+    const reference = referencesByCiteName.get(citeName);
+    if (!reference) {
+      return;
+    }
+    modalReference = reference;
+    modalTitle.textContent = citeName;
+    if (cslCopyButton) {
+      // Fetch the CSL-JSON before it's copied, because browsers only let us
+      // write to the clipboard right after a click.
+      fetchCSL(citeName).catch(() => {});
+    }
+    showFormat();
+    if (citationStyle) {
+      showCitation();
+    }
+    copyStatus.textContent = "";
+    modal.hidden = false;
+    copyButton.focus();
+  }
+
+  function closeBibtex() {
+    modal.hidden = true;
+  }
+
+  document.addEventListener("click", (event) => {
+    const bibtexLink = event.target.closest(".bibtex-link");
+    if (bibtexLink) {
+      event.preventDefault();
+      openBibtex(bibtexLink.dataset.reference);
+      return;
+    }
+    if (event.target.closest("[data-close-bibtex]")) {
+      closeBibtex();
+    }
+  });
+
+  document.addEventListener("keydown", (event) => {
+    if (event.key === "Escape" && !modal.hidden) {
+      closeBibtex();
+    }
+  });
+
+  async function copyText(text) {
+    try {
+      await navigator.clipboard.writeText(text);
+      copyStatus.textContent = "Copied";
+    } catch (error) {
+      copyStatus.textContent = "Copy failed";
+    }
+  }
+
+  copyButton.addEventListener("click", () => {
+    copyText(modalContent.textContent);
+  });
+
+  if (formatSelect) {
+    formatSelect.addEventListener("change", () => {
+      copyStatus.textContent = "";
+      showFormat();
+    });
+  }
+
+  downloadButton.addEventListener("click", () => {
+    if (!modalReference) {
+      return;
+    }
+    const format = formats[selectedFormat()];
+    const link = document.createElement("a");
+    link.href = URL.createObjectURL(new Blob([modalContent.textContent], { type: format.type }));
+    link.download = modalReference.citeName + format.extension;
+    link.click();
+    setTimeout(() => URL.revokeObjectURL(link.href), 0);
+  });
+
+  if (citationStyle) {
+    citationStyle.addEventListener("change", () => {
+      copyStatus.textContent = "";
+      showCitation();
+    });
+
+    citationCopyButton.addEventListener("click", () => {
+      copyText(citationText.textContent);
+    });
+  }
+
+  if (cslCopyButton) {
+    cslCopyButton.addEventListener("click", async () => {
+      if (!modalReference) {
+        return;
+      }
+      const item = await fetchCSL(modalReference.citeName).catch(() => undefined);
+      if (item === undefined) {
+        copyStatus.textContent = "Failed to load censorbib.csl.json";
+        return;
+      }
+      copyText(JSON.stringify([item], null, 2));
+    });
+  }
+
+  closeButton.addEventListener("click", closeBibtex);
+
+  const initialQuery = new URLSearchParams(window.location.search).get("q") || "";
+  input.value = initialQuery;
+  applySearch(initialQuery, false);
+})();
+</script>
+
+</body>
+</html>`
+
+var footerTmpl = template.Must(template.New("footer").Parse(footerTemplate))
+
+// footer returns the page's footer.  If site is set, the modal offers the
+// formats that only the site has.
+func footer(site bool) (string, error) {
+	buf := new(bytes.Buffer)
+	if err := footerTmpl.Execute(buf, struct{ Site bool }{site}); err != nil {
+		return "", fmt.Errorf("error executing footer template: %w", err)
+	}
+	return buf.String() + footerScript, nil
+}
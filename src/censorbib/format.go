@@ -0,0 +1,334 @@
+package censorbib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// The order in which fields appear in a formatted entry.  Fields that aren't
+// listed here come last, in their original order.
+var canonicalFieldOrder = []string{
+	"author",
+	"title",
+	"booktitle",
+	"journal",
+	"volume",
+	"number",
+	"institution",
+	"publisher",
+	"month",
+	"year",
+	"pages",
+	"note",
+	"url",
+	"discussion_url",
+	"added",
+	"abstract",
+}
+
+// Matches the beginning of an entry, e.g.: @inproceedings{Müller2024a,
+var entryHeaderRe = regexp.MustCompile(`^@\s*([a-zA-Z]+)\s*\{\s*([^,\s]+)\s*,`)
+
+// Matches the beginning of a field, e.g.: author =
+var fieldNameRe = regexp.MustCompile(`^([a-zA-Z0-9_-]+)\s*=\s*`)
+
+// rawField is a field of an entry, with its value exactly as it appears in
+// the .bib file, including braces or quotes.
+type rawField struct {
+	name   string
+	value  string
+	offset int // The value's byte offset in the entry's body.
+}
+
+// formattedBlock is a top-level block of a .bib file before and after
+// formatting.
+type formattedBlock struct {
+	offset    int
+	raw       string
+	formatted string
+}
+
+// Format returns the given .bib file in canonical format.  The path is only
+// used in errors.
+func Format(path string, contents []byte) ([]byte, error) {
+	blocks, err := formatBlocks(path, contents)
+	if err != nil {
+		return nil, err
+	}
+	formatted := []string{}
+	for _, block := range blocks {
+		formatted = append(formatted, block.formatted)
+	}
+	if len(formatted) == 0 {
+		return []byte{}, nil
+	}
+	return []byte(strings.Join(formatted, "\n\n") + "\n"), nil
+}
+
+func formatBlocks(path string, contents []byte) ([]formattedBlock, error) {
+	idx := newLineIndex(path, contents)
+	scope := newMacroScope()
+	blocks := []formattedBlock{}
+	end := 0
+	for _, entry := range scanRawBibEntries(contents) {
+		if entry.unterminated {
+			return nil, idx.errorf(entry.offset, "", "", "entry is missing a closing brace")
+		}
+		// BibTeX ignores text between entries, and so do we, but we don't
+		// want to lose it.
+		if gap := strings.TrimSpace(string(contents[end:entry.offset])); gap != "" {
+			blocks = append(blocks, formattedBlock{end, gap, gap})
+		}
+		end = entry.offset + len(entry.raw)
+
+		// Let the parser tell us about syntax errors, so we don't reformat
+		// an entry that we don't understand.
+		citeName, _ := extractCiteName(entry.raw)
+		if _, err := scope.parse(entry.raw); err != nil {
+			return nil, idx.parseError(entry, citeName, err)
+		}
+		formatted, err := formatEntry(entry.raw)
+		if err != nil {
+			return nil, idx.errorf(entry.offset, citeName, "", "%w", err)
+		}
+		blocks = append(blocks, formattedBlock{entry.offset, entry.raw, formatted})
+	}
+	if gap := strings.TrimSpace(string(contents[end:])); gap != "" {
+		blocks = append(blocks, formattedBlock{end, gap, gap})
+	}
+	return blocks, nil
+}
+
+// formatEntry formats a raw entry canonically: fields are indented by a tab,
+// appear in canonical order, and each one ends with a comma.  Field values
+// are left exactly as they are.  The entry must have been parsed
+// successfully.
+func formatEntry(raw string) (string, error) {
+	header := entryHeaderRe.FindStringSubmatch(raw)
+	if header == nil {
+		// This is not a regular entry, e.g. @comment.  Leave it alone.
+		return raw, nil
+	}
+	entryType, citeName := strings.ToLower(header[1]), header[2]
+	switch entryType {
+	case "comment", "preamble", "string":
+		return raw, nil
+	}
+
+	fields, err := splitRawFields(raw[len(header[0]):])
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "@%s{%s,\n", entryType, citeName)
+	for _, field := range sortRawFields(fields) {
+		fmt.Fprintf(&b, "\t%s = %s,\n", field.name, field.value)
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+// sortRawFields returns the given fields in canonical order.
+func sortRawFields(fields []rawField) []rawField {
+	sorted := []rawField{}
+	for _, name := range canonicalFieldOrder {
+		for _, field := range fields {
+			if strings.ToLower(field.name) == name {
+				sorted = append(sorted, field)
+			}
+		}
+	}
+	for _, field := range fields {
+		if !isCanonicalField(field.name) {
+			sorted = append(sorted, field)
+		}
+	}
+	return sorted
+}
+
+func isCanonicalField(name string) bool {
+	for _, canonical := range canonicalFieldOrder {
+		if strings.ToLower(name) == canonical {
+			return true
+		}
+	}
+	return false
+}
+
+// splitRawFields splits the body of an entry, i.e., everything after the
+// cite name and its comma, into fields.
+func splitRawFields(body string) ([]rawField, error) {
+	fields := []rawField{}
+	s := body
+	for {
+		s = strings.TrimLeft(s, " \t\r\n,")
+		if s == "" {
+			return nil, errors.New("entry is missing a closing brace")
+		}
+		if s[0] == '}' {
+			if rest := strings.TrimSpace(s[1:]); rest != "" {
+				return nil, fmt.Errorf("unexpected text after entry: %q", rest)
+			}
+			return fields, nil
+		}
+
+		name := fieldNameRe.FindStringSubmatch(s)
+		if name == nil {
+			return nil, fmt.Errorf("expected field but got %q", firstLine(s))
+		}
+		s = s[len(name[0]):]
+		n, err := rawValueLen(s)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", name[1], err)
+		}
+		fields = append(fields, rawField{name[1], strings.TrimSpace(s[:n]), len(body) - len(s)})
+		s = s[n:]
+	}
+}
+
+// rawValueLen returns the length of the field value at the beginning of s.
+// A value consists of braced strings, quoted strings, and bare words like
+// numbers and string variables, which may be concatenated using #.
+func rawValueLen(s string) (int, error) {
+	i := 0
+	for {
+		switch {
+		case i >= len(s):
+			return 0, io.ErrUnexpectedEOF
+		case s[i] == '{':
+			depth := 0
+			for ; i < len(s); i++ {
+				if s[i] == '{' {
+					depth++
+				} else if s[i] == '}' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+			if i == len(s) {
+				return 0, errors.New("unbalanced braces")
+			}
+			i++
+		case s[i] == '"':
+			depth := 0
+			for i++; i < len(s); i++ {
+				if s[i] == '{' {
+					depth++
+				} else if s[i] == '}' {
+					depth--
+				} else if s[i] == '"' && depth == 0 {
+					break
+				}
+			}
+			if i == len(s) {
+				return 0, errors.New("unterminated quoted string")
+			}
+			i++
+		default:
+			start := i
+			for i < len(s) && !strings.ContainsRune(" \t\r\n,}#", rune(s[i])) {
+				i++
+			}
+			if i == start {
+				return 0, fmt.Errorf("expected value but got %q", firstLine(s[i:]))
+			}
+		}
+
+		// Is the value concatenated with another one?
+		j := i
+		for j < len(s) && strings.ContainsRune(" \t\r\n", rune(s[j])) {
+			j++
+		}
+		if j >= len(s) || s[j] != '#' {
+			return i, nil
+		}
+		i = j + 1
+		for i < len(s) && strings.ContainsRune(" \t\r\n", rune(s[i])) {
+			i++
+		}
+	}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// diffLines returns a line-based diff of a and b, with removed lines
+// prefixed by "-", added lines prefixed by "+", and unchanged lines prefixed
+// by a space.  Entries are short, so a simple longest common subsequence is
+// good enough.
+func diffLines(a, b string) []string {
+	x, y := strings.Split(a, "\n"), strings.Split(b, "\n")
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := []string{}
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			diff = append(diff, " "+x[i])
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "-"+x[i])
+			i++
+		default:
+			diff = append(diff, "+"+y[j])
+			j++
+		}
+	}
+	return diff
+}
+
+// FormatDiff returns a diff between the given .bib file and its formatted
+// version, with one hunk per block that isn't formatted.  The diff is empty if
+// the file is formatted.
+func FormatDiff(path string, contents []byte) (string, error) {
+	formatted, err := Format(path, contents)
+	if err != nil {
+		return "", err
+	}
+	if string(formatted) == string(contents) {
+		return "", nil
+	}
+
+	blocks, err := formatBlocks(path, contents)
+	if err != nil {
+		return "", err
+	}
+	idx := newLineIndex(path, contents)
+	var b strings.Builder
+	for _, block := range blocks {
+		if block.raw == block.formatted {
+			continue
+		}
+		line, _ := idx.position(block.offset)
+		fmt.Fprintf(&b, "--- %s:%d\n", path, line)
+		for _, l := range diffLines(block.raw, block.formatted) {
+			b.WriteString(l + "\n")
+		}
+	}
+	if b.Len() == 0 {
+		fmt.Fprintf(&b, "--- %s: whitespace between entries differs\n", path)
+	}
+	return b.String(), nil
+}
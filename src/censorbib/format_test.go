@@ -0,0 +1,98 @@
+package censorbib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatEntry(t *testing.T) {
+	raw := `@InProceedings{Doe2024a,
+    title       = {A {Paper}, with "quotes"},
+  author = "Jane Doe and {John} Doe",
+	url = {https://example.com/paper.pdf},
+	howpublished = {Online},
+	month = aug,
+	booktitle = "Workshop on " # foci,
+	year = 2024
+}`
+	want := `@inproceedings{Doe2024a,
+	author = "Jane Doe and {John} Doe",
+	title = {A {Paper}, with "quotes"},
+	booktitle = "Workshop on " # foci,
+	month = aug,
+	year = 2024,
+	url = {https://example.com/paper.pdf},
+	howpublished = {Online},
+}`
+
+	got, err := formatEntry(raw)
+	if err != nil {
+		t.Fatalf("failed to format entry: %v", err)
+	}
+	if got != want {
+		t.Fatalf("Expected\n%s\ngot\n%s", want, got)
+	}
+
+	// Formatting is idempotent.
+	again, err := formatEntry(got)
+	if err != nil {
+		t.Fatalf("failed to format entry: %v", err)
+	}
+	if again != got {
+		t.Fatalf("Expected\n%s\ngot\n%s", got, again)
+	}
+}
+
+func TestFormatBibFile(t *testing.T) {
+	contents := `Text between entries is kept.
+@misc{Doe2024a,
+  title = {First},
+  author = {Jane Doe}}
+
+
+@misc{Doe2024b,
+	author = {Jane Doe},
+	title = {Second},
+}`
+	want := `Text between entries is kept.
+
+@misc{Doe2024a,
+	author = {Jane Doe},
+	title = {First},
+}
+
+@misc{Doe2024b,
+	author = {Jane Doe},
+	title = {Second},
+}
+`
+	got, err := Format("test.bib", []byte(contents))
+	if err != nil {
+		t.Fatalf("failed to format file: %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("Expected\n%s\ngot\n%s", want, got)
+	}
+
+	// Syntax errors are reported at the same position as lint reports them.
+	broken := "Text.\n\n@misc{Doe2024a,\n\ttitle = {First}\n\tauthor = {Jane Doe},\n}"
+	_, err = Format("test.bib", []byte(broken))
+	if err == nil {
+		t.Fatal("expected formatting an entry with a syntax error to fail")
+	}
+	want = "test.bib:5:7: Doe2024a: syntax error"
+	if err.Error() != want {
+		t.Errorf("expected error %q, got %q", want, err)
+	}
+	if lint := Lint("test.bib", []byte(broken)); len(lint) != 1 || lint[0].Error() != want {
+		t.Errorf("expected lint to report %q, got %v", want, lint)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := diffLines("a\nb\nc", "b\nc\nd")
+	want := []string{"-a", " b", " c", "+d"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Expected\n%v\ngot\n%v", want, got)
+	}
+}
@@ -1,25 +1,15 @@
-package main
+package censorbib
 
 import (
 	"bytes"
+	"fmt"
 	"html/template"
-	"log"
 	"time"
 )
 
-const headerTemplate = `
-<!DOCTYPE html>
-<html lang="en">
-
-<head>
-  <meta http-equiv="Content-Type" content="text/html; charset=utf-8">
-  <title>The Internet censorship bibliography</title>
-  <link rel="icon" href="assets/favicon-32.png"  sizes="32x32">
-  <link rel="icon" href="assets/favicon-128.png" sizes="128x128">
-  <link rel="icon" href="assets/favicon-180.png" sizes="180x180">
-  <link rel="icon" href="assets/favicon-192.png" sizes="192x192">
-  <style>
-  body {
+// The style sheet that all pages share.  Templates that include it must be
+// parsed together with it.
+const styleTemplate = `{{define "style"}}  body {
     font-family: Roboto, Helvetica, sans-serif;
     background: #ddd;
     margin: 1em auto;
@@ -76,6 +66,17 @@ const headerTemplate = `
   .other {
     color: #666;
   }
+  .new-badge {
+    display: inline-block;
+    margin-left: 0.4em;
+    padding: 0 0.4em;
+    font-size: 0.8em;
+    font-weight: bold;
+    color: #fff;
+    background: #d9534f;
+    border-radius: 4px;
+    vertical-align: middle;
+  }
   #container mark {
     background: #ffb772;
     border-radius: 2px;
@@ -213,6 +214,10 @@ const headerTemplate = `
     border-radius: 6px;
     background: #fff;
   }
+  #search-form #new-only-toggle {
+    font-weight: normal;
+    white-space: nowrap;
+  }
   #result-count {
     color: #666;
     white-space: nowrap;
@@ -259,6 +264,9 @@ const headerTemplate = `
     padding: 0.75em;
     border-bottom: 1px solid #c0c0c0;
   }
+  #bibtex-format {
+    font: inherit;
+  }
   #bibtex-title {
     flex: 1;
     margin: 0;
@@ -279,6 +287,52 @@ const headerTemplate = `
     line-height: 1.35;
     background: #fff;
   }
+  #citation-box {
+    display: flex;
+    align-items: center;
+    gap: 0.5em;
+    padding: 0.75em;
+    border-top: 1px solid #c0c0c0;
+  }
+  #citation-style {
+    font: inherit;
+  }
+  #citation-text {
+    flex: 1;
+    margin: 0;
+    font-size: 0.9em;
+  }
+  .paper-page {
+    padding: 1em 1.5em;
+  }
+  .paper-page h1 {
+    float: none;
+    width: auto;
+    color: #333;
+    font-size: 1.5em;
+  }
+  .paper-details {
+    display: grid;
+    grid-template-columns: max-content 1fr;
+    gap: 0.25em 1em;
+    margin: 1em 0;
+  }
+  .paper-details dt {
+    color: #666;
+  }
+  .paper-details dd {
+    margin: 0;
+    overflow-wrap: anywhere;
+  }
+  .raw-bibtex {
+    margin: 0;
+    padding: 1em;
+    white-space: pre-wrap;
+    font-size: 0.9em;
+    background: #fff;
+    border-radius: 6px;
+    border: 1px solid #c0c0c0;
+  }
   @media (max-width: 720px) {
     #header,
     .flex-row {
@@ -296,7 +350,26 @@ const headerTemplate = `
       white-space: normal;
     }
   }
-  </style>
+{{end}}`
+
+const headerTemplate = `
+<!DOCTYPE html>
+<html lang="en">
+
+<head>
+  <meta http-equiv="Content-Type" content="text/html; charset=utf-8">
+  <title>The Internet censorship bibliography</title>
+  <link rel="icon" href="assets/favicon-32.png"  sizes="32x32">
+  <link rel="icon" href="assets/favicon-128.png" sizes="128x128">
+  <link rel="icon" href="assets/favicon-180.png" sizes="180x180">
+  <link rel="icon" href="assets/favicon-192.png" sizes="192x192">
+{{- if .Site}}
+  <link rel="alternate" type="application/atom+xml" title="CensorBib (Atom)" href="feed.atom">
+  <link rel="alternate" type="application/rss+xml" title="CensorBib (RSS)" href="feed.rss">
+  <link rel="alternate" type="application/json" title="CensorBib (JSON)" href="censorbib.json">
+{{- end}}
+  <style>
+{{template "style"}}  </style>
 </head>
 
 <body>
@@ -329,6 +402,17 @@ const headerTemplate = `
             <img class="top-icon" src="assets/update-icon.svg" alt="update icon">
             <a href="https://github.com/NullHypothesis/censorbib/commits/master">Updated: {{.Date}}</a>
           </div>
+          {{- if .Site}}
+          <div class="menu-item">
+            <img class="top-icon" src="assets/bibtex-icon.svg" alt="download icon">
+            Download:
+            <a href="references.bib">BibTeX</a>,
+            <a href="censorbib.ris">RIS</a>,
+            <a href="censorbib.xml">EndNote XML</a>,
+            <a href="censorbib.csl.json">CSL-JSON</a>,
+            <a href="censorbib.json">JSON</a>
+          </div>
+          {{- end}}
         </div> <!-- censorbib-links -->
 
       </div>
@@ -351,17 +435,25 @@ const headerTemplate = `
 
   </div>`
 
-var headerTmpl = template.Must(template.New("header").Parse(headerTemplate))
+var headerTmpl = template.Must(template.New("header").Parse(headerTemplate + styleTemplate))
 
-func header() string {
+// header returns the page's header, which says when the bibliography was last
+// updated.  The zero time means now.  If site is set, the header links to the
+// files that only WriteSite writes.
+func header(updated time.Time, site bool) (string, error) {
+	if updated.IsZero() {
+		updated = time.Now()
+	}
 	i := struct {
 		Date string
+		Site bool
 	}{
-		Date: time.Now().UTC().Format(time.DateOnly),
+		Date: updated.UTC().Format(time.DateOnly),
+		Site: site,
 	}
 	buf := new(bytes.Buffer)
 	if err := headerTmpl.Execute(buf, i); err != nil {
-		log.Fatalf("Error executing template: %v", err)
+		return "", fmt.Errorf("error executing header template: %w", err)
 	}
-	return buf.String()
+	return buf.String(), nil
 }
@@ -0,0 +1,426 @@
+package censorbib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nickng/bibtex"
+)
+
+type searchEntry struct {
+	CiteName  string `json:"citeName"`
+	Title     string `json:"title"`
+	Authors   string `json:"authors"`
+	Venue     string `json:"venue"`
+	Year      string `json:"year"`
+	Publisher string `json:"publisher"`
+	Added     string `json:"added,omitempty"`
+	RawBibtex string `json:"rawBibtex"`
+}
+
+type bibEntryView struct {
+	CiteName      string
+	Title         string
+	Authors       string
+	Venue         string
+	VenuePrefix   string
+	HasVenue      bool
+	Year          string
+	HasYear       bool
+	Publisher     string
+	URL           string
+	CachedURL     string
+	DiscussionURL string
+	// The entry's OpenURL ContextObject, if we embed COinS spans.
+	COinS string
+	// When the entry was added, as RFC 3339 timestamp, if we know it.
+	Added string
+	// Whether the entry was added recently enough to get a badge.
+	IsNew bool
+	// The relative URL of the entry's own page, if there is one.
+	PageURL string
+}
+
+// entryOptions are the optional parts of the entries' HTML.
+type entryOptions struct {
+	// Embed a COinS span in every entry.
+	coins bool
+	// Entries added after this time are marked as new.  The zero time means
+	// that no entry is new.
+	newSince time.Time
+	// Link every entry to its own page, which only exists on the static
+	// site that WriteSite writes.
+	paperPages bool
+}
+
+var bibEntryTemplate = template.Must(template.New("bib-entry").Parse(`<li id="{{.CiteName}}"{{if .Added}} data-added="{{.Added}}"{{end}}>
+<div>
+<span class="paper">{{.Title}}</span>{{if .IsNew}}
+<span class="new-badge" title="Recently added">New</span>{{end}}
+<span class="icons">
+{{if .DiscussionURL}}<a href="{{.DiscussionURL}}"><img class="icon" title="Online discussion" src="assets/discussion-icon.svg" alt="Discussion icon"></a>{{end}}
+<a href="{{.URL}}"><img class="icon" title="Download paper" src="assets/pdf-icon.svg" alt="Download icon"></a>
+<a href="{{.CachedURL}}"><img class="icon" title="Download cached paper" src="assets/cache-icon.svg" alt="Cached download icon"></a>
+<a href="#bibtex-{{.CiteName}}" class="bibtex-link" data-reference="{{.CiteName}}" title="Show BibTeX" aria-label="Show BibTeX for {{.Title}}"><img class="icon" src="assets/bibtex-icon.svg" alt="BibTeX icon"></a>
+<a href="{{if .PageURL}}{{.PageURL}}{{else}}#{{.CiteName}}{{end}}"><img class="icon" title="Link to paper" src="assets/link-icon.svg" alt="Paper link icon"></a>
+</span>
+</div>
+<div>
+<span class="author">{{.Authors}}</span>
+</div>
+<span class="other">{{if .HasVenue}}{{.VenuePrefix}}<span class="venue">{{.Venue}}</span>{{end}}{{if .Year}}{{if .HasVenue}}, {{end}}{{.Year}}{{end}}{{if .Publisher}}{{if or .HasVenue .HasYear}}, {{end}}{{.Publisher}}{{end}}</span>
+{{if .COinS}}<span class="Z3988" title="{{.COinS}}"></span>
+{{end}}</li>
+`))
+
+// WriteEntries writes the HTML list items of the given entries to w, grouped
+// by year.
+func WriteEntries(to io.Writer, bibEntries []Entry) error {
+	return writeEntries(to, bibEntries, entryOptions{})
+}
+
+// writeEntries is like WriteEntries, but with the given optional parts.
+func writeEntries(to io.Writer, bibEntries []Entry, opts entryOptions) error {
+	ew := &errWriter{w: to}
+	previousYear := ""
+	for _, entry := range bibEntries {
+		year := toStr(entry.Fields["year"])
+		if year != previousYear {
+			if previousYear != "" {
+				ew.println("</ul>")
+			}
+			ew.printf("<ul class=\"year-group\" data-year=\"%s\">\n", template.HTMLEscapeString(year))
+			previousYear = year
+		}
+		html, err := makeBibEntry(&entry, opts)
+		if err != nil {
+			return err
+		}
+		ew.print(html)
+	}
+	if previousYear != "" {
+		ew.println("</ul>")
+	}
+	return ew.err
+}
+
+func makeBibEntry(entry *Entry, opts entryOptions) (string, error) {
+	view := entryView(entry)
+	if opts.coins {
+		view.COinS = coinsContext(entry)
+	}
+	view.Added = formatAdded(entry.Added)
+	view.IsNew = !opts.newSince.IsZero() && entry.Added.After(opts.newSince)
+	if opts.paperPages {
+		view.PageURL = paperLink(entry.CiteName)
+	}
+	buf := new(bytes.Buffer)
+	if err := bibEntryTemplate.Execute(buf, view); err != nil {
+		return "", &Error{CiteName: entry.CiteName, Err: err}
+	}
+	return buf.String(), nil
+}
+
+func entryView(entry *Entry) bibEntryView {
+	prefix, venue := entryVenueParts(entry)
+	year := toStr(entry.Fields["year"])
+	return bibEntryView{
+		CiteName:      entry.CiteName,
+		Title:         entryTitle(entry),
+		Authors:       entryAuthors(entry),
+		Venue:         venue,
+		VenuePrefix:   prefix,
+		HasVenue:      venue != "",
+		Year:          year,
+		HasYear:       year != "",
+		Publisher:     entryPublisher(entry),
+		URL:           toStr(entry.Fields["url"]),
+		CachedURL:     cachedURL(entry.CiteName),
+		DiscussionURL: toStr(entry.Fields["discussion_url"]),
+	}
+}
+
+// cachedURL returns the URL of our cached copy of the given entry's paper.
+func cachedURL(citeName string) string {
+	return "https://censorbib-papers.t3.tigrisfiles.io/" + citeName + ".pdf"
+}
+
+func entryTitle(entry *Entry) string {
+	return DecodeTitle(toStr(entry.Fields["title"]))
+}
+
+func entryAuthors(entry *Entry) string {
+	return DecodeAuthors(toStr(entry.Fields["author"]))
+}
+
+// firstAuthorSortKey returns the first author's name, last name first.
+func firstAuthorSortKey(entry *Entry) string {
+	names, _ := entryNames(entry)
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0].sortKey()
+}
+
+// entryField returns the decoded value of the given field, or the empty
+// string if the entry lacks it.
+func entryField(entry *Entry, name string) string {
+	return decodeLaTeX(toStr(entry.Fields[name]))
+}
+
+// entryNames returns the entry's parsed authors, without a trailing
+// "others", and whether the list ends in "others".  An author field that
+// doesn't parse, which lint reports, yields no authors.
+func entryNames(entry *Entry) (names []Name, others bool) {
+	parsed, err := ParseAuthors(toStr(entry.Fields["author"]))
+	if err != nil {
+		return nil, false
+	}
+	for _, name := range parsed {
+		if name.isOthers() {
+			others = true
+		} else {
+			names = append(names, name)
+		}
+	}
+	return names, others
+}
+
+func entryPublisher(entry *Entry) string {
+	return DecodePublisher(toStr(entry.Fields["publisher"]))
+}
+
+// entryInstitutionOrPublisher returns the institution that issued a
+// technical report, and the entry's publisher if there is no institution.
+func entryInstitutionOrPublisher(entry *Entry) string {
+	if institution := entryField(entry, "institution"); institution != "" {
+		return institution
+	}
+	return entryPublisher(entry)
+}
+
+func entryVenue(entry *Entry) string {
+	_, venue := entryVenueParts(entry)
+	return venue
+}
+
+func entryVenueParts(entry *Entry) (string, string) {
+	var (
+		prefix string
+		bs     bibtex.BibString
+		ok     bool
+	)
+
+	if bs, ok = entry.Fields["booktitle"]; ok {
+		prefix = "In Proc. of: "
+	} else if bs, ok = entry.Fields["journal"]; ok {
+		prefix = "In: "
+	} else {
+		return "", "" // Some entries are self-published.
+	}
+
+	return prefix, DecodeProceedings(toStr(bs))
+}
+
+func sortEntries(bibEntries []Entry) {
+	sort.SliceStable(bibEntries, func(i, j int) bool {
+		a := &bibEntries[i]
+		b := &bibEntries[j]
+		for _, cmp := range []struct {
+			left       string
+			right      string
+			descending bool
+		}{
+			{toStr(a.Fields["year"]), toStr(b.Fields["year"]), true},
+			{entryVenue(a), entryVenue(b), false},
+			{entryTitle(a), entryTitle(b), false},
+			{firstAuthorSortKey(a), firstAuthorSortKey(b), false},
+			{a.CiteName, b.CiteName, false},
+		} {
+			left := strings.ToLower(cmp.left)
+			right := strings.ToLower(cmp.right)
+			if left == right {
+				continue
+			}
+			if cmp.descending {
+				return left > right
+			}
+			return left < right
+		}
+		return false
+	})
+}
+
+// WriteHTML writes the bibliography's HTML page to w.  The bibliography is
+// sorted first.
+func WriteHTML(w io.Writer, bib *Bibliography) error {
+	return writeHTML(w, bib, false)
+}
+
+// writeHTML is like WriteHTML.  If site is set, the page links to the files
+// that only WriteSite writes, like the feeds and the entries' own pages.
+func writeHTML(w io.Writer, bib *Bibliography, site bool) error {
+	opts := bib.entryOptions()
+	opts.paperPages = site
+	bib.Sort()
+	bibEntries := bib.Entries()
+	header, err := header(bib.updated, site)
+	if err != nil {
+		return err
+	}
+
+	ew := &errWriter{w: w}
+	ew.print(header)
+	if err := makeSearchBox(ew, len(bibEntries)); err != nil {
+		return err
+	}
+	ew.println("<div id='container'>")
+	if err := writeEntries(ew, bibEntries, opts); err != nil {
+		return err
+	}
+	ew.println("</div>")
+	if err := WriteReferenceData(ew, bibEntries); err != nil {
+		return err
+	}
+	footer, err := footer(site)
+	if err != nil {
+		return err
+	}
+	ew.print(footer)
+	return ew.err
+}
+
+// WriteReferenceData writes the JSON data that the page's search and BibTeX
+// modal use, wrapped in a <script> element.
+func WriteReferenceData(w io.Writer, bibEntries []Entry) error {
+	searchEntries := []searchEntry{}
+	for _, entry := range bibEntries {
+		searchEntries = append(searchEntries, searchEntry{
+			CiteName:  entry.CiteName,
+			Title:     entryTitle(&entry),
+			Authors:   entryAuthors(&entry),
+			Venue:     entryVenue(&entry),
+			Year:      toStr(entry.Fields["year"]),
+			Publisher: entryPublisher(&entry),
+			Added:     formatAdded(entry.Added),
+			RawBibtex: entry.RawBibtex,
+		})
+	}
+
+	ew := &errWriter{w: w}
+	ew.println(`<script id="reference-data" type="application/json">`)
+	if err := json.NewEncoder(ew).Encode(searchEntries); err != nil {
+		return fmt.Errorf("failed to encode search data: %w", err)
+	}
+	ew.println(`</script>`)
+	return ew.err
+}
+
+// linkedDataArticle is a Schema.org JSON-LD document with a single paper.
+type linkedDataArticle struct {
+	Context string `json:"@context"`
+	scholarlyArticle
+}
+
+type scholarlyArticle struct {
+	Type          string       `json:"@type"`
+	ID            string       `json:"@id"`
+	Headline      string       `json:"headline"`
+	Author        []linkedName `json:"author,omitempty"`
+	DatePublished string       `json:"datePublished,omitempty"`
+	IsPartOf      *publication `json:"isPartOf,omitempty"`
+	Publisher     *linkedName  `json:"publisher,omitempty"`
+	Pagination    string       `json:"pagination,omitempty"`
+	URL           string       `json:"url,omitempty"`
+	SameAs        []string     `json:"sameAs,omitempty"`
+	DiscussionURL string       `json:"discussionUrl,omitempty"`
+}
+
+// linkedName is a Person or an Organization.
+type linkedName struct {
+	Type       string `json:"@type"`
+	Name       string `json:"name"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// publication is what a paper is part of: a Periodical, a PublicationVolume,
+// or a PublicationIssue.
+type publication struct {
+	Type         string       `json:"@type"`
+	Name         string       `json:"name,omitempty"`
+	VolumeNumber string       `json:"volumeNumber,omitempty"`
+	IssueNumber  string       `json:"issueNumber,omitempty"`
+	IsPartOf     *publication `json:"isPartOf,omitempty"`
+}
+
+const schemaOrgContext = "https://schema.org"
+
+func makeScholarlyArticle(entry *Entry) scholarlyArticle {
+	view := entryView(entry)
+	article := scholarlyArticle{
+		Type:          "ScholarlyArticle",
+		ID:            paperURL(entry.CiteName),
+		Headline:      view.Title,
+		DatePublished: view.Year,
+		Pagination:    entryField(entry, "pages"),
+		URL:           view.URL,
+		SameAs:        []string{view.CachedURL},
+		DiscussionURL: view.DiscussionURL,
+	}
+	names, _ := entryNames(entry)
+	for _, name := range names {
+		article.Author = append(article.Author, makeLinkedName(name))
+	}
+	if view.Publisher != "" {
+		article.Publisher = &linkedName{Type: "Organization", Name: view.Publisher}
+	}
+
+	switch entry.Type {
+	case "article":
+		// Periodical ⊃ PublicationVolume ⊃ PublicationIssue, leaving out the
+		// levels that we know nothing about.
+		article.IsPartOf = &publication{Type: "Periodical", Name: view.Venue}
+		if volume := entryField(entry, "volume"); volume != "" {
+			article.IsPartOf = &publication{Type: "PublicationVolume", VolumeNumber: volume, IsPartOf: article.IsPartOf}
+		}
+		if number := entryField(entry, "number"); number != "" {
+			article.IsPartOf = &publication{Type: "PublicationIssue", IssueNumber: number, IsPartOf: article.IsPartOf}
+		}
+	case "inproceedings":
+		article.IsPartOf = &publication{Type: "PublicationIssue", Name: view.Venue}
+	}
+	return article
+}
+
+// makeLinkedName turns an author into a Person, unless the author is a braced
+// corporate name like {The Tor Project}.
+func makeLinkedName(name Name) linkedName {
+	if isCorporate(name) {
+		return linkedName{Type: "Organization", Name: name.String()}
+	}
+	return linkedName{
+		Type:       "Person",
+		Name:       name.String(),
+		GivenName:  decodeLaTeX(name.First),
+		FamilyName: decodeLaTeX(strings.Join(nonEmpty(name.Von, name.Last), " ")),
+	}
+}
+
+func makeSearchBox(to io.Writer, count int) error {
+	_, err := fmt.Fprintf(to, `<form id="search-form" role="search" action="">
+  <label for="search-input">Search</label>
+  <input id="search-input" type="search" name="q" autocomplete="off" placeholder="Title, author, venue, year, publisher, or cite name">
+  <label id="new-only-toggle" hidden><input id="new-only" type="checkbox"> Show only new</label>
+  <span id="result-count" aria-live="polite">%d papers</span>
+</form>
+<div id="no-results" hidden>No matches.</div>
+`, count)
+	return err
+}
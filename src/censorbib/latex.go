@@ -0,0 +1,315 @@
+package censorbib
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Accent commands, mapped to pairs of (letter, accented letter).  The pairs
+// were generated by composing each ASCII letter with the accent's combining
+// character, and keeping the ones for which Unicode has a precomposed
+// character.
+var accentPairs = map[string]string{
+	`'`: "AÁaáCĆcćEÉeéGǴgǵIÍiíKḰkḱLĹlĺMḾmḿNŃnńOÓoóPṔpṕRŔrŕSŚsśUÚuúWẂwẃYÝyýZŹzź",
+	"`": "AÀaàEÈeèIÌiìNǸnǹOÒoòUÙuùWẀwẁYỲyỳ",
+	`^`: "AÂaâCĈcĉEÊeêGĜgĝHĤhĥIÎiîJĴjĵOÔoôSŜsŝUÛuûWŴwŵYŶyŷZẐzẑ",
+	`"`: "AÄaäEËeëHḦhḧIÏiïOÖoötẗUÜuüWẄwẅYŸyÿ",
+	`~`: "AÃaãEẼeẽIĨiĩNÑnñOÕoõUŨuũVṼvṽYỸyỹ",
+	`=`: "AĀaāEĒeēGḠgḡIĪiīOŌoōUŪuūYȲyȳ",
+	`.`: "AȦaȧBḂbḃCĊcċDḊdḋEĖeėFḞfḟGĠgġHḢhḣIİMṀmṁNṄnṅOȮoȯPṖpṗRṘrṙSṠsṡTṪtṫWẆwẇYẎyẏZŻzż",
+	`u`: "AĂaăEĔeĕGĞgğIĬiĭOŎoŏUŬuŭ",
+	`v`: "AǍaǎCČcčDĎdďEĚeěGǦgǧHȞhȟIǏiǐjǰKǨkǩLĽlľNŇnňOǑoǒRŘrřSŠsšTŤtťUǓuǔZŽzž",
+	`H`: "OŐoőUŰuű",
+	`c`: "CÇcçDḐdḑEȨeȩGĢgģHḨhḩKĶkķLĻlļNŅnņRŖrŗSŞsşTŢtţ",
+	`k`: "AĄaąEĘeęIĮiįOǪoǫUŲuų",
+	`r`: "AÅaåUŮuůwẘyẙ",
+	`d`: "AẠaạBḄbḅDḌdḍEẸeẹHḤhḥIỊiịKḲkḳLḶlḷMṂmṃNṆnṇOỌoọRṚrṛSṢsṣTṬtṭUỤuụVṾvṿWẈwẉYỴyỵZẒzẓ",
+	`b`: "BḆbḇDḎdḏhẖKḴkḵLḺlḻNṈnṉRṞrṟTṮtṯZẔzẕ",
+}
+
+// The combining characters of accent commands, for letters that have no
+// precomposed accented character.
+var accentMarks = map[string]rune{
+	`'`: '́', "`": '̀', `^`: '̂', `"`: '̈', `~`: '̃',
+	`=`: '̄', `.`: '̇', `u`: '̆', `v`: '̌', `H`: '̋',
+	`c`: '̧', `k`: '̨', `r`: '̊', `d`: '̣', `b`: '̱',
+}
+
+var accents = func() map[string]map[rune]rune {
+	accents := make(map[string]map[rune]rune)
+	for accent, pairs := range accentPairs {
+		runes := []rune(pairs)
+		accents[accent] = make(map[rune]rune)
+		for i := 0; i+1 < len(runes); i += 2 {
+			accents[accent][runes[i]] = runes[i+1]
+		}
+	}
+	return accents
+}()
+
+// Commands that stand for a character or string, in and outside of math mode.
+var textCommands = map[string]string{
+	// Special letters.
+	"ss": "ß", "SS": "SS", "o": "ø", "O": "Ø", "ae": "æ", "AE": "Æ",
+	"oe": "œ", "OE": "Œ", "aa": "å", "AA": "Å", "l": "ł", "L": "Ł",
+	"i": "ı", "j": "ȷ", "dh": "ð", "DH": "Ð", "th": "þ", "TH": "Þ",
+	"ng": "ŋ", "NG": "Ŋ",
+	// Dashes, quotes, and punctuation.
+	"textendash": "–", "textemdash": "—", "textquoteleft": "‘",
+	"textquoteright": "’", "textquotedblleft": "“", "textquotedblright": "”",
+	"guillemotleft": "«", "guillemotright": "»", "guilsinglleft": "‹",
+	"guilsinglright": "›", "quotedblbase": "„", "quotesinglbase": "‚",
+	"textexclamdown": "¡", "textquestiondown": "¿", "ldots": "…", "dots": "…",
+	"textellipsis": "…", "textbullet": "•", "textperiodcentered": "·",
+	// Symbols.
+	"S": "§", "P": "¶", "dag": "†", "ddag": "‡", "copyright": "©",
+	"textcopyright": "©", "textregistered": "®", "texttrademark": "™",
+	"textdegree": "°", "euro": "€", "texteuro": "€", "pounds": "£",
+	"textsterling": "£", "textyen": "¥", "textbackslash": `\`,
+	"textasciitilde": "~", "textasciicircum": "^", "textbar": "|",
+	"textless": "<", "textgreater": ">", "textunderscore": "_",
+	"LaTeX": "LaTeX", "TeX": "TeX", "BibTeX": "BibTeX",
+}
+
+// Commands that only make sense in math mode, e.g. $\rightarrow$.
+var mathCommands = map[string]string{
+	"rightarrow": "→", "to": "→", "leftarrow": "←", "gets": "←",
+	"leftrightarrow": "↔", "Rightarrow": "⇒", "Leftarrow": "⇐",
+	"Leftrightarrow": "⇔", "uparrow": "↑", "downarrow": "↓", "mapsto": "↦",
+	"cdot": "·", "times": "×", "div": "÷", "pm": "±", "mp": "∓",
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠",
+	"approx": "≈", "sim": "∼", "simeq": "≃", "equiv": "≡", "propto": "∝",
+	"ll": "≪", "gg": "≫", "infty": "∞", "partial": "∂", "nabla": "∇",
+	"in": "∈", "notin": "∉", "subset": "⊂", "subseteq": "⊆", "cup": "∪",
+	"cap": "∩", "emptyset": "∅", "forall": "∀", "exists": "∃", "neg": "¬",
+	"wedge": "∧", "land": "∧", "vee": "∨", "lor": "∨", "oplus": "⊕",
+	"otimes": "⊗", "sum": "∑", "prod": "∏", "int": "∫", "sqrt": "√",
+	"circ": "∘", "star": "⋆", "ast": "∗", "bullet": "•", "prime": "′",
+	"ldots": "…", "cdots": "⋯", "dots": "…", "langle": "⟨", "rangle": "⟩",
+	"lvert": "|", "rvert": "|", "mid": "∣",
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ε",
+	"varepsilon": "ε", "zeta": "ζ", "eta": "η", "theta": "θ", "iota": "ι",
+	"kappa": "κ", "lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ", "pi": "π",
+	"rho": "ρ", "sigma": "σ", "tau": "τ", "upsilon": "υ", "phi": "φ",
+	"varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω", "Gamma": "Γ",
+	"Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π",
+	"Sigma": "Σ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+}
+
+// Commands whose argument we display as-is, without the command's
+// formatting, e.g. \emph{not}.
+var formattingCommands = map[string]bool{
+	"emph": true, "textit": true, "textbf": true, "texttt": true,
+	"textsc": true, "textrm": true, "textsf": true, "textsl": true,
+	"textup": true, "textmd": true, "textnormal": true, "mbox": true,
+	"hbox": true, "url": true, "mathrm": true, "mathit": true,
+	"mathbf": true, "mathsf": true, "mathtt": true, "mathcal": true,
+	"text": true, "ensuremath": true, "NoCaseChange": true,
+}
+
+var (
+	superscripts = map[rune]rune{
+		'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴', '5': '⁵', '6': '⁶',
+		'7': '⁷', '8': '⁸', '9': '⁹', '+': '⁺', '-': '⁻', 'n': 'ⁿ', 'i': 'ⁱ',
+	}
+	subscripts = map[rune]rune{
+		'0': '₀', '1': '₁', '2': '₂', '3': '₃', '4': '₄', '5': '₅', '6': '₆',
+		'7': '₇', '8': '₈', '9': '₉', '+': '₊', '-': '₋',
+	}
+)
+
+// Character sequences that (La)TeX turns into other characters outside of
+// math mode.  Longer sequences must come first.
+var ligatures = []conversion{
+	{"---", "—"},
+	{"--", "–"},
+	{"``", "“"},
+	{"''", "”"},
+	{"`", "‘"},
+	{"'", "’"}, // U+2019
+	{"~", " "},
+}
+
+// decodeLaTeX turns the given LaTeX into plain Unicode text.  It handles
+// accents and special letters, dashes, quotes, non-breaking spaces, common
+// text and formatting commands, and simple math.  Curly braces are removed,
+// so the result is meant for display, not for further processing as LaTeX.
+func decodeLaTeX(s string) string {
+	d := &latexDecoder{s: s}
+	return d.decode(false)
+}
+
+type latexDecoder struct {
+	s   string
+	pos int
+}
+
+func (d *latexDecoder) peek() (rune, int) {
+	if d.pos >= len(d.s) {
+		return utf8.RuneError, 0
+	}
+	return utf8.DecodeRuneInString(d.s[d.pos:])
+}
+
+// decode decodes until the end of the string, or until the closing brace of
+// the current group, whichever comes first.
+func (d *latexDecoder) decode(math bool) string {
+	var b strings.Builder
+	for d.pos < len(d.s) {
+		r, size := d.peek()
+		switch {
+		case r == '}':
+			return b.String()
+		case r == '{':
+			d.pos += size
+			b.WriteString(d.decode(math))
+			d.pos++ // Skip the closing brace.
+		case r == '\\':
+			d.pos += size
+			b.WriteString(d.command(math))
+		case r == '$':
+			if math {
+				return b.String()
+			}
+			d.pos += size
+			b.WriteString(d.decode(true))
+			d.pos++ // Skip the closing dollar sign.
+		case math && (r == '^' || r == '_'):
+			d.pos += size
+			table := superscripts
+			if r == '_' {
+				table = subscripts
+			}
+			for _, c := range d.argument(math) {
+				if script, ok := table[c]; ok {
+					b.WriteRune(script)
+				} else {
+					b.WriteRune(c)
+				}
+			}
+		default:
+			if !math {
+				if to, ok := d.ligature(); ok {
+					b.WriteString(to)
+					continue
+				}
+			}
+			d.pos += size
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (d *latexDecoder) ligature() (string, bool) {
+	for _, l := range ligatures {
+		if strings.HasPrefix(d.s[d.pos:], l.from) {
+			d.pos += len(l.from)
+			return l.to, true
+		}
+	}
+	return "", false
+}
+
+// command decodes the command right after a backslash.
+func (d *latexDecoder) command(math bool) string {
+	r, size := d.peek()
+	if size == 0 {
+		return `\`
+	}
+
+	// Control symbols, i.e., a backslash followed by a non-letter.
+	if !isASCIILetter(r) {
+		d.pos += size
+		name := string(r)
+		if _, ok := accents[name]; ok && !math {
+			return d.accent(name, math)
+		}
+		switch name {
+		case " ", "\n", "\t":
+			return " "
+		case ",", ";":
+			return " " // Narrow non-breaking space.
+		case "-", "/":
+			return "" // Discretionary hyphen and italic correction.
+		}
+		return name // E.g., \&, \%, \$, \#, \_, \{, and \}.
+	}
+
+	// Control words, i.e., a backslash followed by letters.
+	start := d.pos
+	for d.pos < len(d.s) && isASCIILetter(rune(d.s[d.pos])) {
+		d.pos++
+	}
+	name := d.s[start:d.pos]
+	// Spaces after a control word only terminate it.
+	for d.pos < len(d.s) && (d.s[d.pos] == ' ' || d.s[d.pos] == '\t' || d.s[d.pos] == '\n') {
+		d.pos++
+	}
+
+	if _, ok := accents[name]; ok && len(name) == 1 {
+		return d.accent(name, math)
+	}
+	if formattingCommands[name] {
+		return d.argument(math)
+	}
+	if math {
+		if to, ok := mathCommands[name]; ok {
+			return to
+		}
+	}
+	if to, ok := textCommands[name]; ok {
+		return to
+	}
+	// We don't know this command, so the best we can do is show its name.
+	return name
+}
+
+// accent applies the given accent command to the next argument.
+func (d *latexDecoder) accent(name string, math bool) string {
+	arg := []rune(d.argument(math))
+	if len(arg) == 0 {
+		return ""
+	}
+	letter := arg[0]
+	// Accents on a dotless i or j, e.g. \'{\i}, are accents on an i or j.
+	switch letter {
+	case 'ı':
+		letter = 'i'
+	case 'ȷ':
+		letter = 'j'
+	}
+	if accented, ok := accents[name][letter]; ok {
+		return string(accented) + string(arg[1:])
+	}
+	return string(letter) + string(accentMarks[name]) + string(arg[1:])
+}
+
+// argument decodes a command's argument, which is either a group in curly
+// braces, another command, or a single character.
+func (d *latexDecoder) argument(math bool) string {
+	for d.pos < len(d.s) && d.s[d.pos] == ' ' {
+		d.pos++
+	}
+	r, size := d.peek()
+	switch {
+	case size == 0 || r == '}':
+		return ""
+	case r == '{':
+		d.pos += size
+		arg := d.decode(math)
+		d.pos++ // Skip the closing brace.
+		return arg
+	case r == '\\':
+		d.pos += size
+		return d.command(math)
+	default:
+		d.pos += size
+		return string(r)
+	}
+}
+
+func isASCIILetter(r rune) bool {
+	return r < unicode.MaxASCII && unicode.IsLetter(r)
+}
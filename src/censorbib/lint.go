@@ -0,0 +1,113 @@
+package censorbib
+
+import (
+	"github.com/nickng/bibtex"
+)
+
+// linter collects every problem in a .bib file instead of stopping at the
+// first one.
+type linter struct {
+	index   *lineIndex
+	scope   *macroScope
+	errs    []*Error
+	entries []lintedEntry
+}
+
+// lintedEntry is an entry that we parsed, along with its raw record.  Broken
+// entries failed to parse, and only their cite name is known.
+type lintedEntry struct {
+	raw    rawBibEntry
+	entry  *bibtex.BibEntry
+	broken bool
+}
+
+// Lint checks the given .bib file and returns every problem that it finds,
+// sorted by position.  Unlike Load, Lint doesn't stop at the first broken
+// entry, and it also checks cite names and looks for duplicate papers.
+func Lint(path string, contents []byte) []*Error {
+	l := &linter{index: newLineIndex(path, contents), scope: newMacroScope()}
+	for _, raw := range scanRawBibEntries(contents) {
+		l.lintEntry(raw)
+	}
+	// Entries may inherit fields from parents that come after them, so we
+	// can only check them once we've parsed the whole file.
+	l.lintCrossrefs()
+	for _, e := range l.entries {
+		if !e.broken {
+			l.errs = append(l.errs, checkEntry(l.index, e.raw, e.entry)...)
+		}
+	}
+	l.lintCiteNames()
+	l.lintDuplicates()
+	sortErrors(l.errs)
+	return l.errs
+}
+
+func (l *linter) lintEntry(raw rawBibEntry) {
+	if raw.unterminated {
+		l.errs = append(l.errs, l.index.errorf(raw.offset, "", "", "entry is missing a closing brace"))
+		return
+	}
+	if !isEntry(raw.raw) {
+		if _, err := l.scope.parse(raw.raw); err != nil {
+			l.errs = append(l.errs, l.index.parseError(raw, "", err))
+		}
+		return
+	}
+	citeName, ok := extractCiteName(raw.raw)
+	if !ok {
+		l.errs = append(l.errs, l.index.errorf(raw.offset, "", "", "failed to extract cite name"))
+		return
+	}
+
+	entry, err := l.scope.parseEntry(raw.raw)
+	if err != nil {
+		l.errs = append(l.errs, l.index.parseError(raw, citeName, err))
+		// Keep track of the cite name, so we don't suggest it for other
+		// entries.
+		l.entries = append(l.entries, lintedEntry{raw, bibtex.NewBibEntry("", citeName), true})
+		return
+	}
+	l.entries = append(l.entries, lintedEntry{raw, entry, false})
+}
+
+func (l *linter) lintCrossrefs() {
+	rawByEntry := l.rawByEntry()
+	for _, v := range resolveCrossrefs(l.parsedEntries()) {
+		raw := rawByEntry[v.entry]
+		l.errs = append(l.errs, l.index.errorf(fieldOffset(raw, "crossref"), v.entry.CiteName, "crossref", "%s", v.msg))
+	}
+}
+
+func (l *linter) lintCiteNames() {
+	rawByEntry := l.rawByEntry()
+	for _, v := range checkCiteNames(l.parsedEntries(), citeNameAllowlist) {
+		raw := rawByEntry[v.entry]
+		l.errs = append(l.errs, l.index.errorf(citeNameOffset(raw), v.entry.CiteName, "", "%s", v))
+	}
+}
+
+func (l *linter) lintDuplicates() {
+	rawByEntry := l.rawByEntry()
+	for _, d := range findDuplicates(l.parsedEntries(), duplicateAllowlist) {
+		// Report the duplicate at the second entry of the pair.
+		raw := rawByEntry[d.second]
+		l.errs = append(l.errs, l.index.errorf(citeNameOffset(raw), d.second.CiteName, "", "%s", d))
+	}
+}
+
+func (l *linter) rawByEntry() map[*bibtex.BibEntry]rawBibEntry {
+	rawByEntry := make(map[*bibtex.BibEntry]rawBibEntry)
+	for _, e := range l.entries {
+		rawByEntry[e.entry] = e.raw
+	}
+	return rawByEntry
+}
+
+func (l *linter) parsedEntries() []*bibtex.BibEntry {
+	entries := []*bibtex.BibEntry{}
+	for _, e := range l.entries {
+		entries = append(entries, e.entry)
+	}
+	return entries
+}
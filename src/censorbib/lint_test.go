@@ -0,0 +1,68 @@
+package censorbib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintBibFile(t *testing.T) {
+	contents := `@inproceedings{Doe2024a,
+	author = {Doe, Jr, Jane, Extra},
+	title = {First paper},
+	year = {2024},
+}
+
+@inproceedings{Doe2024b,
+	author = {Jane Doe},
+	title = {Broken paper}
+	year = {2024},
+	url = {https://example.com/broken.pdf},
+}
+
+@inproceedings{Doe2024c,
+	author = {Jane Doe},
+	title = {Third paper},
+	journal = {Journal},
+	booktitle = {Workshop},
+	year = {2024},
+	url = {https://example.com/third.pdf},
+	bookttle = {Workshop},
+}
+`
+	var got []string
+	for _, d := range Lint("test.bib", []byte(contents)) {
+		got = append(got, d.Error())
+	}
+	want := []string{
+		"test.bib:1:1: Doe2024a: booktitle: missing required field",
+		"test.bib:1:1: Doe2024a: url: missing required field",
+		"test.bib:2:2: Doe2024a: author: name \"Doe, Jr, Jane, Extra\" has too many commas",
+		"test.bib:10:5: Doe2024b: syntax error",
+		"test.bib:17:2: Doe2024c: journal: field not allowed in @inproceedings entries",
+		"test.bib:21:2: Doe2024c: bookttle: unknown field; did you mean booktitle?",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected diagnostics:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLineIndex(t *testing.T) {
+	idx := newLineIndex("test.bib", []byte("ab\nüb\n\nc"))
+	for _, test := range []struct {
+		offset int
+		line   int
+		column int
+	}{
+		{0, 1, 1},
+		{1, 1, 2},
+		{3, 2, 1},
+		{5, 2, 2}, // "ü" is two bytes but one column.
+		{7, 3, 1},
+		{8, 4, 1},
+	} {
+		line, column := idx.position(test.offset)
+		if line != test.line || column != test.column {
+			t.Errorf("offset %d: got %d:%d, want %d:%d", test.offset, line, column, test.line, test.column)
+		}
+	}
+}
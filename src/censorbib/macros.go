@@ -0,0 +1,290 @@
+package censorbib
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nickng/bibtex"
+)
+
+// Matches the type of a top-level block, e.g.: @inproceedings or @string
+var blockTypeRe = regexp.MustCompile(`^@\s*([a-zA-Z]+)`)
+
+// Matches the beginning of a string macro definition, e.g.: @string{pets =
+var stringDefRe = regexp.MustCompile(`(?i)^@\s*string\s*\{\s*([a-zA-Z0-9_:.+/'-]+)\s*=\s*`)
+
+// blockType returns the lowercased type of a raw block, e.g. "article" or
+// "string".
+func blockType(raw string) string {
+	matches := blockTypeRe.FindStringSubmatch(raw)
+	if matches == nil {
+		return ""
+	}
+	return strings.ToLower(matches[1])
+}
+
+// isEntry reports whether the given raw block is a bibliography entry, as
+// opposed to a @string, @preamble, or @comment block.
+func isEntry(raw string) bool {
+	switch blockType(raw) {
+	case "string", "preamble", "comment":
+		return false
+	}
+	return true
+}
+
+// undefinedMacroError is returned for a block that uses a string macro that
+// isn't defined before the block.  The vendored parser would exit the
+// process instead of returning an error, so we have to find these
+// ourselves.
+type undefinedMacroError struct {
+	ref macroRef
+}
+
+func (e *undefinedMacroError) Error() string {
+	return fmt.Sprintf("undefined string macro %q", e.ref.name)
+}
+
+// macroRef is a reference to a string macro in a raw block, e.g. "pets" in
+// "journal = pets".
+type macroRef struct {
+	field  string
+	name   string
+	offset int // Relative to the start of the block.
+}
+
+// macroScope keeps track of the string macros that were defined so far, so
+// that blocks which use them can be parsed on their own.
+type macroScope struct {
+	values map[string]string
+	// The @string blocks that we parsed so far.  We prepend them to every
+	// block that we parse.
+	prelude      strings.Builder
+	preludeLines int
+}
+
+func newMacroScope() *macroScope {
+	// The vendored parser defines the three-letter month names for us.
+	values := make(map[string]string)
+	for month := time.January; month <= time.December; month++ {
+		values[strings.ToLower(month.String()[:3])] = month.String()
+	}
+	return &macroScope{values: values}
+}
+
+// parse parses the given raw block, which may use the macros that were
+// defined before it.  If the block defines a macro, later blocks may use it.
+func (m *macroScope) parse(raw string) (*bibtex.BibTex, error) {
+	if blockType(raw) == "comment" {
+		// The vendored parser reads a comment up to the next "@", so it
+		// fails on comments that contain one, e.g., an email address or a
+		// commented-out entry.  Comments mean nothing to us anyway.
+		return bibtex.NewBibTex(), nil
+	}
+	for _, ref := range macroRefs(raw) {
+		if _, ok := m.values[strings.ToLower(ref.name)]; !ok {
+			return nil, &undefinedMacroError{ref}
+		}
+	}
+
+	raw = lowerMacros(raw)
+	bib, err := parseBibTeX(m.prelude.String() + raw)
+	if err != nil {
+		// Make the error's position relative to the block again.
+		var parseErr *bibtex.ErrParse
+		if errors.As(err, &parseErr) && len(parseErr.Pos.Lines) >= m.preludeLines {
+			parseErr.Pos.Lines = parseErr.Pos.Lines[m.preludeLines:]
+		}
+		return nil, err
+	}
+
+	if matches := stringDefRe.FindStringSubmatchIndex(raw); matches != nil {
+		if n, err := rawValueLen(raw[matches[1]:]); err == nil {
+			m.values[strings.ToLower(raw[matches[2]:matches[3]])] = m.expand(raw[matches[1] : matches[1]+n])
+		}
+		m.prelude.WriteString(raw + "\n")
+		m.preludeLines += strings.Count(raw, "\n") + 1
+	}
+	for _, entry := range bib.Entries {
+		m.expandFields(raw, entry)
+	}
+	return bib, nil
+}
+
+// expandFields sets the values of the entry's fields that use macros or
+// concatenation.  The vendored parser drops everything but the first part
+// of concatenated values, so we expand these ourselves.
+func (m *macroScope) expandFields(raw string, entry *bibtex.BibEntry) {
+	header := entryHeaderRe.FindString(raw)
+	if header == "" {
+		return
+	}
+	fields, err := splitRawFields(raw[len(header):])
+	if err != nil {
+		return
+	}
+	for _, field := range fields {
+		parts := valueParts(field.value)
+		if len(parts) > 1 || isMacro(parts[0].value) {
+			entry.Fields[field.name] = bibtex.NewBibConst(m.expand(field.value))
+		}
+	}
+}
+
+// expand returns the given raw field value with its parts concatenated and
+// its macros replaced by their values.
+func (m *macroScope) expand(value string) string {
+	var b strings.Builder
+	for _, part := range valueParts(value) {
+		switch {
+		case isMacro(part.value):
+			b.WriteString(m.values[strings.ToLower(part.value)])
+		case strings.HasPrefix(part.value, "{") || strings.HasPrefix(part.value, `"`):
+			b.WriteString(part.value[1 : len(part.value)-1])
+		default:
+			b.WriteString(part.value)
+		}
+	}
+	return b.String()
+}
+
+// lowerMacros returns the given raw block with the names of the macros that
+// it defines or uses lowercased.  BibTeX ignores the case of macro names, but
+// the vendored parser doesn't.
+func lowerMacros(raw string) string {
+	b := []byte(raw)
+	lower := func(offset int, name string) {
+		// Keep offsets intact, in case lowercasing changes the length.
+		if lowered := strings.ToLower(name); len(lowered) == len(name) {
+			copy(b[offset:], lowered)
+		}
+	}
+	if matches := stringDefRe.FindStringSubmatchIndex(raw); matches != nil {
+		lower(matches[2], raw[matches[2]:matches[3]])
+	}
+	for _, ref := range macroRefs(raw) {
+		lower(ref.offset, ref.name)
+	}
+	return string(b)
+}
+
+// parseEntry is like parse, but expects the block to be a single entry.
+func (m *macroScope) parseEntry(raw string) (*bibtex.BibEntry, error) {
+	bib, err := m.parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(bib.Entries) != 1 {
+		return nil, errors.New("expected exactly one entry")
+	}
+	return bib.Entries[0], nil
+}
+
+// macroRefs returns the string macros that the given raw entry or @string
+// block uses, in the order in which they appear.
+func macroRefs(raw string) []macroRef {
+	fields := []rawField{}
+	if loc := stringDefRe.FindStringIndex(raw); loc != nil {
+		n, err := rawValueLen(raw[loc[1]:])
+		if err != nil {
+			return nil // The parser will complain about this.
+		}
+		fields = append(fields, rawField{value: raw[loc[1] : loc[1]+n], offset: loc[1]})
+	} else if loc := entryHeaderRe.FindStringIndex(raw); loc != nil {
+		entryFields, err := splitRawFields(raw[loc[1]:])
+		if err != nil {
+			return nil
+		}
+		for _, field := range entryFields {
+			field.offset += loc[1]
+			fields = append(fields, field)
+		}
+	}
+
+	refs := []macroRef{}
+	for _, field := range fields {
+		for _, part := range valueParts(field.value) {
+			if isMacro(part.value) {
+				refs = append(refs, macroRef{field.name, part.value, field.offset + part.offset})
+			}
+		}
+	}
+	return refs
+}
+
+// valuePart is one of the strings that are concatenated using # in a field
+// value, along with its byte offset in the value.
+type valuePart struct {
+	value  string
+	offset int
+}
+
+// valueParts splits a raw field value into its concatenated parts.
+func valueParts(value string) []valuePart {
+	parts := []valuePart{}
+	depth, quoted, start := 0, false, 0
+	addPart := func(end int) {
+		part := value[start:end]
+		trimmed := strings.TrimLeft(part, " \t\r\n")
+		parts = append(parts, valuePart{
+			value:  strings.TrimSpace(trimmed),
+			offset: start + len(part) - len(trimmed),
+		})
+	}
+	for i, r := range value {
+		switch {
+		case r == '{':
+			depth++
+		case r == '}':
+			depth--
+		case r == '"' && depth == 0:
+			quoted = !quoted
+		case r == '#' && depth == 0 && !quoted:
+			addPart(i)
+			start = i + 1
+		}
+	}
+	addPart(len(value))
+	return parts
+}
+
+// isMacro reports whether the given part of a field value refers to a string
+// macro, i.e., it's neither braced, nor quoted, nor a number.
+func isMacro(part string) bool {
+	if part == "" || part[0] == '{' || part[0] == '"' {
+		return false
+	}
+	return strings.Trim(part, "0123456789") != ""
+}
+
+// expandRawMacros returns the given raw entry with all string macros
+// replaced by their values, which we take from the parsed entry.  Entries
+// that don't use macros are returned as-is.
+func expandRawMacros(raw string, entry *bibtex.BibEntry) string {
+	header := entryHeaderRe.FindStringSubmatch(raw)
+	if header == nil || len(macroRefs(raw)) == 0 {
+		return raw
+	}
+	fields, err := splitRawFields(raw[len(header[0]):])
+	if err != nil {
+		return raw
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "@%s{%s,\n", strings.ToLower(header[1]), header[2])
+	for _, field := range fields {
+		value := field.value
+		for _, part := range valueParts(value) {
+			if isMacro(part.value) {
+				value = "{" + toStr(entry.Fields[field.name]) + "}"
+				break
+			}
+		}
+		fmt.Fprintf(&b, "\t%s = %s,\n", field.name, value)
+	}
+	b.WriteString("}")
+	return b.String()
+}
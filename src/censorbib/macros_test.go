@@ -0,0 +1,165 @@
+package censorbib
+
+import (
+	"strings"
+	"testing"
+)
+
+const macroBib = `@comment{Venues that many entries share.}
+
+@preamble{"\\newcommand{\\noop}[1]{}"}
+
+@string{pets = {Privacy Enhancing Technologies}}
+
+@string{foci = "Free and Open Communications on the Internet"}
+
+@article{Doe2024a,
+	author = {Jane Doe},
+	title = {First paper},
+	journal = pets,
+	month = aug,
+	year = {2024},
+	url = {https://example.com/first.pdf},
+}
+
+@inproceedings{Doe2024b,
+	author = {Jane Doe},
+	title = {Second paper},
+	booktitle = foci # { Workshop},
+	year = 2024,
+	url = {https://example.com/second.pdf},
+}
+`
+
+func TestLoadMacros(t *testing.T) {
+	bib, err := Load(strings.NewReader(macroBib))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+	entries := bib.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries but got %d", len(entries))
+	}
+	if venue := entryVenue(&entries[0]); venue != "Privacy Enhancing Technologies" {
+		t.Errorf("unexpected venue %q", venue)
+	}
+	if venue := entryVenue(&entries[1]); venue != "Free and Open Communications on the Internet Workshop" {
+		t.Errorf("unexpected venue %q", venue)
+	}
+	if !strings.Contains(entries[0].RawBibtex, "journal = pets,") {
+		t.Errorf("expected raw BibTeX to keep the macro:\n%s", entries[0].RawBibtex)
+	}
+
+	bib.ExpandMacros()
+	want := `@article{Doe2024a,
+	author = {Jane Doe},
+	title = {First paper},
+	journal = {Privacy Enhancing Technologies},
+	month = {August},
+	year = {2024},
+	url = {https://example.com/first.pdf},
+}`
+	if got := bib.Entries()[0].RawBibtex; got != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, got)
+	}
+	want = `@inproceedings{Doe2024b,
+	author = {Jane Doe},
+	title = {Second paper},
+	booktitle = {Free and Open Communications on the Internet Workshop},
+	year = 2024,
+	url = {https://example.com/second.pdf},
+}`
+	if got := bib.Entries()[1].RawBibtex; got != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestCommentsWithAt(t *testing.T) {
+	comments := []string{
+		`@comment{@misc{Foo2024a, title = {x}}}`,
+		`@comment{Contact: jane@example.com}`,
+	}
+	for _, comment := range comments {
+		contents := comment + "\n\n" + strings.SplitN(macroBib, "\n\n", 2)[1]
+		if _, err := Load(strings.NewReader(contents)); err != nil {
+			t.Errorf("failed to load bibliography with %s: %v", comment, err)
+		}
+		if errs := Lint("test.bib", []byte(contents)); len(errs) > 0 {
+			t.Errorf("unexpected diagnostics for %s: %v", comment, errs)
+		}
+		formatted, err := Format("test.bib", []byte(contents))
+		if err != nil {
+			t.Errorf("failed to format bibliography with %s: %v", comment, err)
+		} else if !strings.HasPrefix(string(formatted), comment+"\n") {
+			t.Errorf("expected formatting to keep %s, got\n%s", comment, formatted)
+		}
+	}
+}
+
+func TestMacroCase(t *testing.T) {
+	// BibTeX ignores the case of macro names.
+	contents := `@STRING{PETS = {Privacy Enhancing Technologies}}
+
+@article{Doe2024a,
+	author = {Jane Doe},
+	title = {Paper},
+	journal = pets # { Symposium},
+	month = Mar,
+	year = {2024},
+	url = {https://example.com/paper.pdf},
+}
+`
+	if errs := Lint("test.bib", []byte(contents)); len(errs) > 0 {
+		t.Errorf("unexpected diagnostics: %v", errs)
+	}
+	bib, err := Load(strings.NewReader(contents))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+	entry := &bib.Entries()[0]
+	if venue := entryVenue(entry); venue != "Privacy Enhancing Technologies Symposium" {
+		t.Errorf("unexpected venue %q", venue)
+	}
+	if month := toStr(entry.Fields["month"]); month != "March" {
+		t.Errorf("unexpected month %q", month)
+	}
+	if !strings.Contains(entry.RawBibtex, "journal = pets # { Symposium},") || !strings.Contains(entry.RawBibtex, "month = Mar,") {
+		t.Errorf("expected raw BibTeX to keep the macros as written:\n%s", entry.RawBibtex)
+	}
+}
+
+func TestUndefinedMacros(t *testing.T) {
+	// The macro is defined, but only after the entry that uses it.
+	contents := strings.Replace(macroBib, "@string{pets", "@string{later", 1) + "\n@string{pets = {PETS}}\n"
+	want := "test.bib:12:12: Doe2024a: journal: undefined string macro \"pets\""
+	if _, err := parseBib("test.bib", []byte(contents)); err == nil || err.Error() != want {
+		t.Fatalf("Expected\n%s\ngot\n%v", want, err)
+	}
+
+	var got []string
+	for _, err := range Lint("test.bib", []byte(contents)) {
+		got = append(got, err.Error())
+	}
+	if strings.Join(got, "\n") != want {
+		t.Fatalf("Expected\n%s\ngot\n%s", want, strings.Join(got, "\n"))
+	}
+
+	if _, err := Format("test.bib", []byte(contents)); err == nil {
+		t.Fatal("expected formatting a file with an undefined macro to fail")
+	}
+}
+
+func TestMacroRefs(t *testing.T) {
+	raw := `@misc{Doe2024a,
+	title = "A" # b # {C} # 2024 # d,
+	month = aug,
+}`
+	var got []string
+	for _, ref := range macroRefs(raw) {
+		got = append(got, ref.field+":"+ref.name+"@"+raw[ref.offset:ref.offset+len(ref.name)])
+	}
+	want := []string{"title:b@b", "title:d@d", "month:aug@aug"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+}
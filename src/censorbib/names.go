@@ -0,0 +1,260 @@
+package censorbib
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Name is an author's name, split into the four parts that BibTeX knows
+// about.  The parts are LaTeX, as written in the .bib file.
+type Name struct {
+	First string
+	Von   string
+	Last  string
+	Jr    string
+}
+
+// String returns the name the way we display it, e.g. "Ludwig van Beethoven"
+// or "Martin Luther King, Jr.".
+func (n Name) String() string {
+	if n.isOthers() {
+		return "et al."
+	}
+	name := strings.Join(nonEmpty(n.First, n.Von, n.Last), " ")
+	if n.Jr != "" {
+		name += ", " + n.Jr
+	}
+	return decodeLaTeX(name)
+}
+
+// Inverted returns the name with its last name first, e.g. "van Beethoven,
+// Ludwig" or "King, Martin Luther, Jr.", which is how citation metadata
+// expects names.
+func (n Name) Inverted() string {
+	name := strings.Join(nonEmpty(n.Von, n.Last), " ")
+	if n.First != "" {
+		name += ", " + n.First
+	}
+	if n.Jr != "" {
+		name += ", " + n.Jr
+	}
+	return decodeLaTeX(name)
+}
+
+// LastName returns the name's decoded last part, without the von part, e.g.
+// "Beethoven" for "Ludwig van Beethoven".
+func (n Name) LastName() string {
+	return decodeLaTeX(n.Last)
+}
+
+// sortKey returns a key that sorts names by last name first.
+func (n Name) sortKey() string {
+	return strings.ToLower(decodeLaTeX(strings.Join(nonEmpty(n.Last, n.First, n.Von, n.Jr), " ")))
+}
+
+// isCorporate reports whether the name is a braced corporate name like {The
+// Tor Project}, which we never split into parts or abbreviate.
+func isCorporate(name Name) bool {
+	return name.First == "" && strings.HasPrefix(name.Last, "{")
+}
+
+// isOthers reports whether the name is BibTeX's "others", as in "Jane Doe and
+// others".
+func (n Name) isOthers() bool {
+	return n.First == "" && n.Von == "" && n.Jr == "" && n.Last == "others"
+}
+
+// ParseAuthors parses the value of an author field into names.  Names are
+// separated by "and", and each name can take any of BibTeX's forms: "First
+// von Last", "von Last, First", or "von Last, Jr, First".  Braces protect
+// their content, so "{The Tor Project}" is a single last name and "{Jane and
+// John Consulting}" is a single author.
+func ParseAuthors(authors string) ([]Name, error) {
+	if strings.TrimSpace(authors) == "" {
+		return nil, errors.New("author list is empty")
+	}
+	if err := checkBraces(authors); err != nil {
+		return nil, err
+	}
+	names := []Name{}
+	for _, name := range splitNames(authors) {
+		n, err := parseName(name)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, n)
+	}
+	return names, nil
+}
+
+func checkBraces(s string) error {
+	depth := 0
+	for _, r := range s {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth < 0 {
+				return errors.New("unbalanced closing brace")
+			}
+		}
+	}
+	if depth > 0 {
+		return errors.New("unbalanced opening brace")
+	}
+	return nil
+}
+
+// splitNames splits an author list on the word "and", unless it's inside
+// braces.
+func splitNames(authors string) []string {
+	names := []string{}
+	words := splitWords(authors, isNameSpace)
+	start := 0
+	for i, word := range words {
+		if strings.EqualFold(word, "and") {
+			names = append(names, strings.Join(words[start:i], " "))
+			start = i + 1
+		}
+	}
+	return append(names, strings.Join(words[start:], " "))
+}
+
+// parseName parses a single name, following BibTeX's rules.
+func parseName(name string) (Name, error) {
+	parts := splitCommas(name)
+	var n Name
+	switch len(parts) {
+	case 1: // First von Last
+		words := splitWords(parts[0], isNameSpace)
+		if len(words) == 0 {
+			return n, errors.New("empty name")
+		}
+		// The von part starts with the first lowercase word and ends with the
+		// last lowercase word, but the last word is always part of the last
+		// name.
+		vonStart, vonEnd := -1, -1
+		for i, word := range words[:len(words)-1] {
+			if isVonWord(word) {
+				if vonStart < 0 {
+					vonStart = i
+				}
+				vonEnd = i + 1
+			}
+		}
+		if vonStart < 0 {
+			n.First = strings.Join(words[:len(words)-1], " ")
+			n.Last = words[len(words)-1]
+		} else {
+			n.First = strings.Join(words[:vonStart], " ")
+			n.Von = strings.Join(words[vonStart:vonEnd], " ")
+			n.Last = strings.Join(words[vonEnd:], " ")
+		}
+	case 2: // von Last, First
+		n.Von, n.Last = splitVonLast(parts[0])
+		n.First = strings.Join(splitWords(parts[1], isNameSpace), " ")
+	case 3: // von Last, Jr, First
+		n.Von, n.Last = splitVonLast(parts[0])
+		n.Jr = strings.Join(splitWords(parts[1], isNameSpace), " ")
+		n.First = strings.Join(splitWords(parts[2], isNameSpace), " ")
+	default:
+		return n, fmt.Errorf("name %q has too many commas", name)
+	}
+	if n.Last == "" {
+		return n, fmt.Errorf("name %q has no last name", name)
+	}
+	return n, nil
+}
+
+// splitVonLast splits "von Last" into its von and last parts.  The von part
+// consists of all leading lowercase words, except the last word, which is
+// always part of the last name.
+func splitVonLast(s string) (string, string) {
+	words := splitWords(s, isNameSpace)
+	if len(words) == 0 {
+		return "", ""
+	}
+	vonEnd := 0
+	for i, word := range words[:len(words)-1] {
+		if isVonWord(word) {
+			vonEnd = i + 1
+		}
+	}
+	return strings.Join(words[:vonEnd], " "), strings.Join(words[vonEnd:], " ")
+}
+
+// isVonWord reports whether the given word starts with a lowercase letter,
+// which makes it part of a von part.  Words that start with a brace group,
+// like "{de}", are treated as uppercase, unless the group is a special
+// character, like "{\"u}ber".
+func isVonWord(word string) bool {
+	if strings.HasPrefix(word, "{") && !strings.HasPrefix(word, `{\`) {
+		return false
+	}
+	for _, r := range decodeLaTeX(word) {
+		if unicode.IsLetter(r) {
+			return unicode.IsLower(r)
+		}
+	}
+	return false
+}
+
+func isNameSpace(r rune) bool {
+	return unicode.IsSpace(r) || r == '~'
+}
+
+// splitWords splits s around runs of runes for which isSep returns true,
+// unless they are inside braces.  Empty words are dropped.
+func splitWords(s string, isSep func(rune) bool) []string {
+	words := []string{}
+	depth, start := 0, 0
+	for i, r := range s {
+		switch {
+		case r == '{':
+			depth++
+		case r == '}':
+			depth--
+		case depth == 0 && isSep(r):
+			if i > start {
+				words = append(words, s[start:i])
+			}
+			start = i + utf8.RuneLen(r)
+		}
+	}
+	if start < len(s) {
+		words = append(words, s[start:])
+	}
+	return words
+}
+
+// splitCommas splits a name on commas outside of braces, keeping empty parts.
+func splitCommas(s string) []string {
+	parts := []string{}
+	depth, start := 0, 0
+	for i, r := range s {
+		switch {
+		case r == '{':
+			depth++
+		case r == '}':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+func nonEmpty(parts ...string) []string {
+	nonEmpty := []string{}
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return nonEmpty
+}
@@ -0,0 +1,92 @@
+package censorbib
+
+import (
+	"testing"
+)
+
+func TestParseAuthors(t *testing.T) {
+	for _, test := range []struct {
+		authors string
+		want    []Name
+	}{
+		{"Jane Doe", []Name{{First: "Jane", Last: "Doe"}}},
+		{"Doe, Jane", []Name{{First: "Jane", Last: "Doe"}}},
+		{"Jane Doe and John Roe", []Name{{First: "Jane", Last: "Doe"}, {First: "John", Last: "Roe"}}},
+		{"Ludwig van Beethoven", []Name{{First: "Ludwig", Von: "van", Last: "Beethoven"}}},
+		{"van Beethoven, Ludwig", []Name{{First: "Ludwig", Von: "van", Last: "Beethoven"}}},
+		{"King, Jr., Martin Luther", []Name{{First: "Martin Luther", Last: "King", Jr: "Jr."}}},
+		{"Jean de la Fontaine", []Name{{First: "Jean", Von: "de la", Last: "Fontaine"}}},
+		{"Hooman {Mohajeri Moghaddam}", []Name{{First: "Hooman", Last: "{Mohajeri Moghaddam}"}}},
+		{"{The Tor Project}", []Name{{Last: "{The Tor Project}"}}},
+		{"{Jane and John Consulting} and Harry", []Name{{Last: "{Jane and John Consulting}"}, {Last: "Harry"}}},
+		{`Kurt G{\"o}del`, []Name{{First: "Kurt", Last: `G{\"o}del`}}},
+		{"Jane~Doe and others", []Name{{First: "Jane", Last: "Doe"}, {Last: "others"}}},
+	} {
+		got, err := ParseAuthors(test.authors)
+		if err != nil {
+			t.Errorf("ParseAuthors(%q): unexpected error: %v", test.authors, err)
+			continue
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("ParseAuthors(%q): expected %q, got %q", test.authors, test.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("ParseAuthors(%q): expected %#v, got %#v", test.authors, test.want[i], got[i])
+			}
+		}
+	}
+
+	for _, authors := range []string{
+		"",
+		"Jane Doe and and John Roe",
+		"Doe, Jr, Jane, Extra",
+		", Jane",
+		"{Jane Doe",
+		"Jane Doe}",
+	} {
+		if _, err := ParseAuthors(authors); err == nil {
+			t.Errorf("ParseAuthors(%q): expected an error", authors)
+		}
+	}
+}
+
+func TestNameInverted(t *testing.T) {
+	testCases := []conversion{
+		{from: "Ludwig van Beethoven", to: "van Beethoven, Ludwig"},
+		{from: "King, Jr., Martin Luther", to: "King, Martin Luther, Jr."},
+		{from: "{The Tor Project}", to: "The Tor Project"},
+		{from: `Kurt G{\"o}del`, to: "Gödel, Kurt"},
+	}
+
+	for _, test := range testCases {
+		names, err := ParseAuthors(test.from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := names[0].Inverted(); got != test.to {
+			t.Errorf("Expected\n%s\ngot\n%s", test.to, got)
+		}
+	}
+}
+
+func TestNameString(t *testing.T) {
+	testCases := []conversion{
+		{from: "van Beethoven, Ludwig", to: "Ludwig van Beethoven"},
+		{from: "King, Jr., Martin Luther", to: "Martin Luther King, Jr."},
+		{from: "{The Tor Project}", to: "The Tor Project"},
+		{from: `G{\"o}del, Kurt`, to: "Kurt Gödel"},
+		{from: "others", to: "et al."},
+	}
+
+	for _, test := range testCases {
+		names, err := ParseAuthors(test.from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := names[0].String(); got != test.to {
+			t.Errorf("Expected\n%s\ngot\n%s", test.to, got)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package censorbib
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// The URL under which CensorBib is deployed.  Mirrors link to it as the
+// canonical copy.
+const siteURL = "https://censorbib.nymity.ch/"
+
+// paperDetail is a row in the table of a paper's metadata.
+type paperDetail struct {
+	Name  string
+	Value string
+	URL   string
+}
+
+// metaTag is a <meta> element with a name, like the Highwire Press tags that
+// Google Scholar and Zotero read, e.g. citation_title.
+type metaTag struct {
+	Name    string
+	Content string
+}
+
+type paperView struct {
+	bibEntryView
+	PageURL     string
+	Description string
+	Details     []paperDetail
+	Citation    []metaTag
+	LinkedData  linkedDataArticle
+	RawBibtex   string
+}
+
+// Paper pages live two directories below the site's root, so they use a
+// <base> element to resolve the same relative links as index.html.
+const paperTemplate = `<!DOCTYPE html>
+<html lang="en">
+
+<head>
+  <meta http-equiv="Content-Type" content="text/html; charset=utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <base href="../../">
+  <title>{{.Title}} – CensorBib</title>
+  <meta name="description" content="{{.Description}}">
+  <link rel="canonical" href="{{.PageURL}}">
+  <meta property="og:type" content="article">
+  <meta property="og:site_name" content="CensorBib">
+  <meta property="og:title" content="{{.Title}}">
+  <meta property="og:description" content="{{.Description}}">
+  <meta property="og:url" content="{{.PageURL}}">
+  <meta name="twitter:card" content="summary">
+{{range .Citation}}  <meta name="{{.Name}}" content="{{.Content}}">
+{{end}}  <link rel="icon" href="assets/favicon-32.png"  sizes="32x32">
+  <link rel="icon" href="assets/favicon-128.png" sizes="128x128">
+  <link rel="icon" href="assets/favicon-180.png" sizes="180x180">
+  <link rel="icon" href="assets/favicon-192.png" sizes="192x192">
+  <style>
+{{template "style"}}  </style>
+  <script type="application/ld+json">{{.LinkedData}}</script>
+</head>
+
+<body>
+
+<div id="container">
+<p><a href="./#{{.CiteName}}">← All papers in CensorBib</a></p>
+<div class="paper-page round-shadow">
+<h1>{{.Title}}</h1>
+<dl class="paper-details">
+{{range .Details}}<dt>{{.Name}}</dt>
+<dd>{{if .URL}}<a href="{{.URL}}">{{.Value}}</a>{{else}}{{.Value}}{{end}}</dd>
+{{end}}</dl>
+<h2>BibTeX</h2>
+<pre class="raw-bibtex">{{.RawBibtex}}</pre>
+</div>
+</div>
+
+</body>
+</html>
+`
+
+var paperTmpl = template.Must(template.New("paper").Parse(paperTemplate + styleTemplate))
+
+// paperPath returns the path of the given entry's page, relative to the
+// site's root directory.
+func paperPath(citeName string) string {
+	return path.Join("p", citeName, "index.html")
+}
+
+// paperLink returns the URL of the given entry's page, relative to the
+// site's root.
+func paperLink(citeName string) string {
+	return "p/" + url.PathEscape(citeName) + "/"
+}
+
+// paperURL returns the absolute URL of the given entry's page.
+func paperURL(citeName string) string {
+	return siteURL + paperLink(citeName)
+}
+
+// WritePaperPage writes the HTML page of a single entry to w.
+func WritePaperPage(w io.Writer, entry *Entry) error {
+	if err := paperTmpl.Execute(w, makePaperView(entry)); err != nil {
+		return &Error{CiteName: entry.CiteName, Err: fmt.Errorf("error executing paper template: %w", err)}
+	}
+	return nil
+}
+
+func makePaperView(entry *Entry) paperView {
+	view := entryView(entry)
+
+	details := []paperDetail{{Name: "Authors", Value: view.Authors}}
+	if view.HasVenue {
+		details = append(details, paperDetail{Name: "Venue", Value: view.Venue})
+	}
+	for _, d := range []paperDetail{
+		{Name: "Volume", Value: entryField(entry, "volume")},
+		{Name: "Number", Value: entryField(entry, "number")},
+		{Name: "Pages", Value: entryField(entry, "pages")},
+		{Name: "Month", Value: entryField(entry, "month")},
+		{Name: "Year", Value: view.Year},
+		{Name: "Publisher", Value: view.Publisher},
+		{Name: "Institution", Value: entryField(entry, "institution")},
+		{Name: "Note", Value: entryField(entry, "note")},
+		{Name: "Paper", Value: view.URL, URL: view.URL},
+		{Name: "Cached paper", Value: view.CachedURL, URL: view.CachedURL},
+		{Name: "Discussion", Value: view.DiscussionURL, URL: view.DiscussionURL},
+	} {
+		if d.Value != "" {
+			details = append(details, d)
+		}
+	}
+
+	return paperView{
+		bibEntryView: view,
+		PageURL:      paperURL(entry.CiteName),
+		Description:  paperDescription(view),
+		Details:      details,
+		Citation:     citationMetaTags(entry, view),
+		LinkedData:   linkedDataArticle{schemaOrgContext, makeScholarlyArticle(entry)},
+		RawBibtex:    entry.RawBibtex,
+	}
+}
+
+// paperDescription returns a one-sentence summary of the given entry, for
+// link previews, e.g.: "By Jane Doe, John Doe. In Proc. of: FOCI, 2024."
+func paperDescription(view bibEntryView) string {
+	parts := []string{}
+	if view.HasVenue {
+		parts = append(parts, view.VenuePrefix+view.Venue)
+	}
+	if view.HasYear {
+		parts = append(parts, view.Year)
+	}
+	description := "By " + view.Authors + "."
+	if len(parts) > 0 {
+		description += " " + strings.Join(parts, ", ") + "."
+	}
+	return description
+}
+
+// citationMetaTags returns the Highwire Press tags for the given entry.
+// Empty tags are left out.
+func citationMetaTags(entry *Entry, view bibEntryView) []metaTag {
+	tags := []metaTag{{"citation_title", view.Title}}
+	names, _ := entryNames(entry)
+	for _, name := range names {
+		tags = append(tags, metaTag{"citation_author", name.Inverted()})
+	}
+	tags = append(tags, metaTag{"citation_publication_date", view.Year})
+
+	switch entry.Type {
+	case "inproceedings":
+		tags = append(tags, metaTag{"citation_conference_title", view.Venue})
+	case "article":
+		tags = append(tags,
+			metaTag{"citation_journal_title", view.Venue},
+			metaTag{"citation_volume", entryField(entry, "volume")},
+			metaTag{"citation_issue", entryField(entry, "number")},
+		)
+	case "techreport":
+		tags = append(tags,
+			metaTag{"citation_technical_report_institution", entryField(entry, "institution")},
+			metaTag{"citation_technical_report_number", entryField(entry, "number")},
+		)
+	}
+	firstPage, lastPage := splitPages(toStr(entry.Fields["pages"]))
+	tags = append(tags,
+		metaTag{"citation_firstpage", firstPage},
+		metaTag{"citation_lastpage", lastPage},
+		metaTag{"citation_publisher", view.Publisher},
+		metaTag{"citation_pdf_url", pdfURL(view)},
+	)
+
+	nonEmpty := []metaTag{}
+	for _, tag := range tags {
+		if tag.Content != "" {
+			nonEmpty = append(nonEmpty, tag)
+		}
+	}
+	return nonEmpty
+}
+
+// splitPages splits a page range like "12--34" into its first and last page.
+func splitPages(pages string) (string, string) {
+	first, last, _ := strings.Cut(pages, "-")
+	return strings.TrimSpace(first), strings.TrimSpace(strings.TrimLeft(last, "-"))
+}
+
+// pdfURL returns the URL of the entry's PDF: the paper's URL if it points to
+// a PDF, and our cached copy otherwise.
+func pdfURL(view bibEntryView) string {
+	if u, err := url.Parse(view.URL); err == nil && strings.HasSuffix(strings.ToLower(u.Path), ".pdf") {
+		return view.URL
+	}
+	return view.CachedURL
+}
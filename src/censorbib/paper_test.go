@@ -0,0 +1,47 @@
+package censorbib
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWritePaperPage(t *testing.T) {
+	entry := mustParse(t, `@inproceedings{Grübl2026a,
+	author = {Thomas Gr{\"u}bl and Jane Doe},
+	title = {A {Paper} about {Censorship} -- Part 1},
+	booktitle = {Free and Open Communications on the Internet},
+	year = {2026},
+	pages = {1--10},
+	url = {https://example.com/paper.pdf},
+	discussion_url = {https://github.com/net4people/bbs/issues/1},
+}`)
+
+	buf := new(bytes.Buffer)
+	if err := WritePaperPage(buf, &entry); err != nil {
+		t.Fatalf("failed to write paper page: %v", err)
+	}
+	page := buf.String()
+	for _, want := range []string{
+		"<title>A Paper about Censorship – Part 1 – CensorBib</title>",
+		`<meta property="og:title" content="A Paper about Censorship – Part 1">`,
+		`<meta name="description" content="By Thomas Grübl, Jane Doe. In Proc. of: Free and Open Communications on the Internet, 2026.">`,
+		`<link rel="canonical" href="https://censorbib.nymity.ch/p/Gr%C3%BCbl2026a/">`,
+		`<dd>1–10</dd>`,
+		`<a href="https://censorbib-papers.t3.tigrisfiles.io/Gr%c3%bcbl2026a.pdf">`,
+		`<a href="https://github.com/net4people/bbs/issues/1">`,
+		`<pre class="raw-bibtex">@inproceedings{Grübl2026a,`,
+		`<meta name="citation_title" content="A Paper about Censorship – Part 1">`,
+		`<meta name="citation_author" content="Grübl, Thomas">
+  <meta name="citation_author" content="Doe, Jane">`,
+		`<meta name="citation_publication_date" content="2026">`,
+		`<meta name="citation_conference_title" content="Free and Open Communications on the Internet">`,
+		`<meta name="citation_firstpage" content="1">
+  <meta name="citation_lastpage" content="10">`,
+		`<meta name="citation_pdf_url" content="https://example.com/paper.pdf">`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected page to contain\n%s", want)
+		}
+	}
+}
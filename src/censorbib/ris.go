@@ -0,0 +1,82 @@
+package censorbib
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The RIS types of our entry types.
+var risTypes = map[string]string{
+	"inproceedings": "CONF",
+	"article":       "JOUR",
+	"techreport":    "RPRT",
+	"misc":          "GEN",
+}
+
+// risTag is a line of an RIS record, e.g. "AU  - Doe, Jane".
+type risTag struct {
+	tag   string
+	value string
+}
+
+// WriteRIS writes the bibliography in the RIS format, which EndNote and
+// Mendeley import, to w.  The bibliography is sorted first.
+func WriteRIS(w io.Writer, bib *Bibliography) error {
+	bib.Sort()
+	ew := &errWriter{w: w}
+	for i, entry := range bib.Entries() {
+		if i > 0 {
+			ew.print("\r\n")
+		}
+		ew.print(formatRIS(&entry))
+	}
+	return ew.err
+}
+
+// formatRIS returns the given entry as an RIS record.  Lines end in CRLF, as
+// the format requires.
+func formatRIS(entry *Entry) string {
+	view := entryView(entry)
+
+	tags := []risTag{{"TY", risTypes[entry.Type]}, {"ID", entry.CiteName}}
+	names, _ := entryNames(entry)
+	for _, name := range names {
+		tags = append(tags, risTag{"AU", name.Inverted()})
+	}
+	tags = append(tags, risTag{"TI", view.Title})
+	switch entry.Type {
+	case "inproceedings":
+		tags = append(tags, risTag{"T2", view.Venue})
+	case "article":
+		tags = append(tags,
+			risTag{"JO", view.Venue},
+			risTag{"VL", entryField(entry, "volume")},
+			risTag{"IS", entryField(entry, "number")},
+		)
+	case "techreport":
+		tags = append(tags, risTag{"SN", entryField(entry, "number")})
+	}
+	firstPage, lastPage := splitPages(toStr(entry.Fields["pages"]))
+	tags = append(tags,
+		risTag{"SP", firstPage},
+		risTag{"EP", lastPage},
+		risTag{"PY", view.Year},
+		risTag{"PB", entryInstitutionOrPublisher(entry)},
+		risTag{"UR", view.URL},
+		risTag{"L1", view.CachedURL},
+		risTag{"AB", entryField(entry, "abstract")},
+		risTag{"N1", entryField(entry, "note")},
+	)
+
+	var b strings.Builder
+	for _, tag := range tags {
+		// Values must be on a single line.
+		value := strings.Join(strings.Fields(tag.value), " ")
+		if value != "" {
+			fmt.Fprintf(&b, "%s  - %s\r\n", tag.tag, value)
+		}
+	}
+	b.WriteString("ER  - \r\n")
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package censorbib
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFormatRIS(t *testing.T) {
+	entry := mustParse(t, `@article{Doe2024a,
+		author = {Jane Doe and Ludwig van Beethoven and others},
+		title = {Journal {Paper}},
+		journal = {Privacy Enhancing Technologies},
+		volume = {2024},
+		number = {4},
+		pages = {12--34},
+		publisher = {Example Publisher},
+		year = {2024},
+		url = {https://example.com/paper.pdf},
+		note = {Two
+			lines},
+	}`)
+
+	want := strings.ReplaceAll(`TY  - JOUR
+ID  - Doe2024a
+AU  - Doe, Jane
+AU  - van Beethoven, Ludwig
+TI  - Journal Paper
+JO  - Privacy Enhancing Technologies
+VL  - 2024
+IS  - 4
+SP  - 12
+EP  - 34
+PY  - 2024
+PB  - Example Publisher
+UR  - https://example.com/paper.pdf
+L1  - https://censorbib-papers.t3.tigrisfiles.io/Doe2024a.pdf
+N1  - Two lines
+ER  - 
+`, "\n", "\r\n")
+	if got := formatRIS(&entry); got != want {
+		t.Errorf("Expected\n%q\ngot\n%q", want, got)
+	}
+}
+
+func TestFormatRISPublisher(t *testing.T) {
+	testCases := []struct {
+		fields string
+		want   string
+	}{
+		{"institution = {Example University},\npublisher = {Example Press},", "PB  - Example University\r\n"},
+		{"publisher = {Example Press},", "PB  - Example Press\r\n"},
+	}
+
+	for _, test := range testCases {
+		entry := mustParse(t, `@techreport{Doe2024a,
+			author = {Jane Doe},
+			title = {Report},
+			year = {2024},
+			url = {https://example.com/report.pdf},
+			`+test.fields+`
+		}`)
+		got := formatRIS(&entry)
+		if n := strings.Count(got, "PB  - "); n != 1 || !strings.Contains(got, test.want) {
+			t.Errorf("Expected one %q in\n%q", test.want, got)
+		}
+	}
+}
+
+func TestWriteRIS(t *testing.T) {
+	bib, err := Load(strings.NewReader(feedBib))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := WriteRIS(buf, bib); err != nil {
+		t.Fatalf("failed to write RIS: %v", err)
+	}
+	got := buf.String()
+	if n := strings.Count(got, "ER  - \r\n"); n != 3 {
+		t.Errorf("expected 3 records but got %d", n)
+	}
+	for _, want := range []string{"TY  - CONF\r\n", "T2  - Free and Open Communications on the Internet\r\n", "TY  - GEN\r\n", "AB  - We study censorship.\r\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RIS missing %q in %q", want, got)
+		}
+	}
+}
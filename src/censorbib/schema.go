@@ -0,0 +1,206 @@
+package censorbib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nickng/bibtex"
+)
+
+// The field names that the compiler knows about.  Anything else is most likely
+// a typo.
+var recognisedFields = map[string]bool{
+	"author":         true,
+	"title":          true,
+	"booktitle":      true,
+	"journal":        true,
+	"publisher":      true,
+	"year":           true,
+	"url":            true,
+	"discussion_url": true,
+	"volume":         true,
+	"number":         true,
+	"pages":          true,
+	"institution":    true,
+	"note":           true,
+	"month":          true,
+	"crossref":       true,
+	"abstract":       true,
+	"added":          true,
+}
+
+// entrySchema describes which fields an entry type must have and may have.
+// Any other field is not allowed.
+type entrySchema struct {
+	required []string
+	optional []string
+	// Parent entries only exist to be referenced by other entries' crossref
+	// field.  They aren't papers, so they don't need the common fields.
+	parent bool
+}
+
+// Fields that every paper must have, regardless of its type.
+var commonRequiredFields = []string{"author", "title", "year", "url"}
+
+// The entry types that CensorBib supports.  The venue of a paper is taken from
+// booktitle or journal (see entryVenueParts), so we are strict about which
+// entry type may use which of the two.
+var entrySchemas = map[string]entrySchema{
+	"inproceedings": {
+		required: []string{"booktitle"},
+		optional: []string{"publisher", "pages", "month", "note", "discussion_url", "crossref", "abstract", "added"},
+	},
+	"article": {
+		required: []string{"journal"},
+		optional: []string{"volume", "number", "pages", "publisher", "month", "note", "discussion_url", "abstract", "added"},
+	},
+	"techreport": {
+		optional: []string{"institution", "number", "month", "note", "discussion_url", "abstract", "added"},
+	},
+	"misc": {
+		optional: []string{"publisher", "month", "note", "discussion_url", "abstract", "added"},
+	},
+	"proceedings": {
+		required: []string{"title", "year"},
+		optional: []string{"booktitle", "publisher", "volume", "number", "month", "note", "url"},
+		parent:   true,
+	},
+}
+
+// requiredFields returns the fields that entries of the schema's type must
+// have, including the common ones.
+func (s entrySchema) requiredFields() []string {
+	if s.parent {
+		return s.required
+	}
+	return append(append([]string{}, commonRequiredFields...), s.required...)
+}
+
+// allowed returns the set of fields that entries of the schema's type may
+// have.
+func (s entrySchema) allowed() map[string]bool {
+	allowed := make(map[string]bool)
+	for _, field := range append(s.requiredFields(), s.optional...) {
+		allowed[field] = true
+	}
+	return allowed
+}
+
+// schemaViolation is a problem with an entry's fields.  The field is empty if
+// the problem concerns the entry as a whole.
+type schemaViolation struct {
+	field string
+	msg   string
+}
+
+func (v schemaViolation) String() string {
+	if v.field == "" {
+		return v.msg
+	}
+	return fmt.Sprintf("%s: %s", v.field, v.msg)
+}
+
+func checkSchema(entry *bibtex.BibEntry) []schemaViolation {
+	schema, ok := entrySchemas[entry.Type]
+	if !ok {
+		return []schemaViolation{{msg: fmt.Sprintf("unsupported entry type @%s", entry.Type)}}
+	}
+
+	allowed := schema.allowed()
+	violations := []schemaViolation{}
+	for _, field := range schema.requiredFields() {
+		if _, ok := entry.Fields[field]; !ok {
+			violations = append(violations, schemaViolation{field, "missing required field"})
+		}
+	}
+	for field := range entry.Fields {
+		switch {
+		case !recognisedFields[field]:
+			violations = append(violations, schemaViolation{field, unknownFieldMsg(field)})
+		case !allowed[field]:
+			violations = append(violations, schemaViolation{
+				field,
+				fmt.Sprintf("field not allowed in @%s entries", entry.Type),
+			})
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].field < violations[j].field
+	})
+	return violations
+}
+
+func unknownFieldMsg(field string) string {
+	if suggestion := suggestField(field); suggestion != "" {
+		return fmt.Sprintf("unknown field; did you mean %s?", suggestion)
+	}
+	return "unknown field"
+}
+
+// suggestField returns the recognised field name that is closest to the given
+// (unknown) field name, or the empty string if none is close enough.
+func suggestField(field string) string {
+	names := []string{}
+	for name := range recognisedFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	suggestion, best := "", max(2, utf8.RuneCountInString(field)/3)+1
+	for _, name := range names {
+		if d := editDistance(strings.ToLower(field), name); d < best {
+			suggestion, best = name, d
+		}
+	}
+	return suggestion
+}
+
+// editDistance returns the number of insertions, deletions, substitutions,
+// and transpositions of adjacent characters that turn a into b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// checkEntry checks the given entry's schema, date added, and authors.
+func checkEntry(idx *lineIndex, raw rawBibEntry, entry *bibtex.BibEntry) []*Error {
+	errs := []*Error{}
+	for _, v := range checkSchema(entry) {
+		offset := raw.offset
+		if _, ok := entry.Fields[v.field]; ok {
+			offset = fieldOffset(raw, v.field)
+		}
+		errs = append(errs, idx.errorf(offset, entry.CiteName, v.field, "%s", v.msg))
+	}
+	if _, err := entryAdded(entry); err != nil {
+		errs = append(errs, idx.errorf(fieldOffset(raw, "added"), entry.CiteName, "added", "%w", err))
+	}
+	if authors, ok := entry.Fields["author"]; ok {
+		if err := validateAuthors(toStr(authors)); err != nil {
+			errs = append(errs, idx.errorf(fieldOffset(raw, "author"), entry.CiteName, "author", "%w", err))
+		}
+	}
+	return errs
+}
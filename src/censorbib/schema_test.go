@@ -0,0 +1,105 @@
+package censorbib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckSchema(t *testing.T) {
+	testCases := []struct {
+		bib  string
+		want []string
+	}{
+		{
+			bib: `@inproceedings{Doe2024a,
+				author = {Jane Doe},
+				title = {Paper},
+				booktitle = {Workshop},
+				year = {2024},
+				url = {https://example.com/paper.pdf},
+			}`,
+			want: nil,
+		},
+		{ // A typo in booktitle should not result in a paper without venue.
+			bib: `@inproceedings{Doe2024a,
+				author = {Jane Doe},
+				title = {Paper},
+				bookttle = {Workshop},
+				year = {2024},
+				url = {https://example.com/paper.pdf},
+			}`,
+			want: []string{
+				"booktitle: missing required field",
+				"bookttle: unknown field; did you mean booktitle?",
+			},
+		},
+		{
+			bib: `@article{Doe2024a,
+				author = {Jane Doe},
+				title = {Paper},
+				journal = {Journal},
+				booktitle = {Workshop},
+				year = {2024},
+			}`,
+			want: []string{
+				"booktitle: field not allowed in @article entries",
+				"url: missing required field",
+			},
+		},
+		{ // Papers that aren't articles have neither volume nor number.
+			bib: `@misc{Doe2024a,
+				author = {Jane Doe},
+				title = {Paper},
+				year = {2024},
+				url = {https://example.com/paper.pdf},
+				volume = {1},
+				number = {2},
+				pages = {3--4},
+			}`,
+			want: []string{
+				"number: field not allowed in @misc entries",
+				"pages: field not allowed in @misc entries",
+				"volume: field not allowed in @misc entries",
+			},
+		},
+		{
+			bib: `@book{Doe2024a,
+				author = {Jane Doe},
+				title = {Book},
+				year = {2024},
+				url = {https://example.com/book.pdf},
+			}`,
+			want: []string{"unsupported entry type @book"},
+		},
+	}
+
+	for _, test := range testCases {
+		entry := mustParse(t, test.bib)
+		var got []string
+		for _, v := range checkSchema(&entry.BibEntry) {
+			got = append(got, v.String())
+		}
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("Expected\n%v\ngot\n%v", test.want, got)
+		}
+	}
+}
+
+func TestSuggestField(t *testing.T) {
+	testCases := []conversion{
+		{from: "disucssion_url", to: "discussion_url"},
+		{from: "bookttle", to: "booktitle"},
+		{from: "Author", to: "author"},
+		{from: "yaer", to: "year"},
+		{from: "urls", to: "url"},
+		{from: "abstrct", to: "abstract"},
+		{from: "keywords", to: ""},
+		{from: "isbn", to: ""},
+	}
+
+	for _, test := range testCases {
+		if got := suggestField(test.from); got != test.to {
+			t.Errorf("suggestField(%q): expected %q, got %q", test.from, test.to, got)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package censorbib
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// siteFile is a file that WriteSite generates, with its path relative to the
+// site's root directory.
+type siteFile struct {
+	path  string
+	write func(io.Writer, *Bibliography) error
+}
+
+// The files that make up the static site, in addition to the assets.
+var siteFiles = []siteFile{
+	{"index.html", writeSiteHTML},
+	{"references.bib", writeBibFile},
+	{"feed.atom", WriteAtom},
+	{"feed.rss", WriteRSS},
+	{"censorbib.json", WriteJSON},
+	{"censorbib.schema.json", WriteJSONSchema},
+	{"censorbib.csl.json", WriteCSLJSON},
+	{"censorbib.ris", WriteRIS},
+	{"censorbib.xml", WriteEndNoteXML},
+	{"censorbib.citations.json", WriteCitations},
+}
+
+// WriteSite writes the static site into the given directory, which is
+// created if necessary: index.html, the other generated files, a page for
+// every paper, and a copy of the assets directory.  The result is
+// self-contained, so it can be served as-is.
+func WriteSite(dir string, bib *Bibliography, assetsDir string) error {
+	for _, file := range siteFiles {
+		err := writeSiteFile(filepath.Join(dir, file.path), func(w io.Writer) error {
+			return file.write(w, bib)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.path, err)
+		}
+	}
+	for _, entry := range bib.Entries() {
+		err := writeSiteFile(filepath.Join(dir, paperPath(entry.CiteName)), func(w io.Writer) error {
+			return WritePaperPage(w, &entry)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write page of %s: %w", entry.CiteName, err)
+		}
+	}
+	if err := copyDir(filepath.Join(dir, "assets"), assetsDir); err != nil {
+		return fmt.Errorf("failed to copy assets: %w", err)
+	}
+	return nil
+}
+
+// writeSiteHTML is like WriteHTML, but links to the other files of the site,
+// like the feeds and the entries' own pages.
+func writeSiteHTML(w io.Writer, bib *Bibliography) error {
+	return writeHTML(w, bib, true)
+}
+
+// writeSiteFile creates the given file, along with its parent directories,
+// and writes to it using write.
+func writeSiteFile(path string, write func(io.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	if err := write(bw); err != nil {
+		f.Close()
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeBibFile writes the .bib file that the bibliography was loaded from,
+// so that visitors can download all of it.
+func writeBibFile(w io.Writer, bib *Bibliography) error {
+	_, err := w.Write(bib.source)
+	return err
+}
+
+// copyDir copies the regular files in the src directory and its
+// subdirectories to dst.
+func copyDir(dst, src string) error {
+	return fs.WalkDir(os.DirFS(src), ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		return writeSiteFile(filepath.Join(dst, path), func(w io.Writer) error {
+			f, err := os.Open(filepath.Join(src, path))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(w, f)
+			return err
+		})
+	})
+}
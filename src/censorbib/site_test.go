@@ -0,0 +1,92 @@
+package censorbib
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteSite(t *testing.T) {
+	assets := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(assets, "icons"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(assets, "icons", "pdf-icon.svg"), []byte("<svg/>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	contents := `@misc{Doe2024a,
+	author = {Jane Doe},
+	title = {Paper},
+	year = {2024},
+	url = {https://example.com/paper.pdf},
+}
+`
+	bib, err := Load(strings.NewReader(contents))
+	if err != nil {
+		t.Fatalf("failed to load bibliography: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "site")
+	if err := WriteSite(dir, bib, assets); err != nil {
+		t.Fatalf("failed to write site: %v", err)
+	}
+	for path, want := range map[string]string{
+		"index.html":                `<li id="Doe2024a">`,
+		"references.bib":            contents,
+		"assets/icons/pdf-icon.svg": "<svg/>",
+		"p/Doe2024a/index.html":     "<title>Paper – CensorBib</title>",
+		"feed.atom":                 `<feed xmlns="http://www.w3.org/2005/Atom">`,
+		"feed.rss":                  `<rss version="2.0"`,
+		"censorbib.citations.json":  `"Doe2024a": {`,
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, path))
+		if err != nil {
+			t.Errorf("expected %s to exist: %v", path, err)
+			continue
+		}
+		if !strings.Contains(string(got), want) {
+			t.Errorf("expected %s to contain %q", path, want)
+		}
+	}
+	// The site's page links to the site's other files.
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range siteLinks {
+		if !strings.Contains(string(index), want) {
+			t.Errorf("expected index.html to contain %q", want)
+		}
+	}
+
+	// Only the papers' own pages embed linked data, which would bloat the
+	// index.
+	if strings.Contains(string(index), "application/ld+json") {
+		t.Error("expected index.html not to embed linked data")
+	}
+
+	// A page on its own doesn't.
+	buf := new(bytes.Buffer)
+	if err := WriteHTML(buf, bib); err != nil {
+		t.Fatalf("failed to write HTML: %v", err)
+	}
+	for _, link := range siteLinks {
+		if strings.Contains(buf.String(), link) {
+			t.Errorf("expected HTML not to contain %q", link)
+		}
+	}
+}
+
+// Links to the files that only WriteSite writes.
+var siteLinks = []string{
+	`<a href="p/Doe2024a/"><img class="icon" title="Link to paper"`,
+	`<link rel="alternate" type="application/atom+xml" title="CensorBib (Atom)" href="feed.atom">`,
+	`<link rel="alternate" type="application/rss+xml" title="CensorBib (RSS)" href="feed.rss">`,
+	`<link rel="alternate" type="application/json" title="CensorBib (JSON)" href="censorbib.json">`,
+	`<a href="censorbib.ris">RIS</a>`,
+	`<option value="ris">RIS</option>`,
+	`<button id="csl-copy" type="button">Copy CSL-JSON</button>`,
+	`<footer id="citation-box">`,
+}
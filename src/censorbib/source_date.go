@@ -0,0 +1,49 @@
+package censorbib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoSourceDate is returned by SourceDate if neither SOURCE_DATE_EPOCH nor
+// the git history of the .bib file is available.
+var ErrNoSourceDate = errors.New("neither SOURCE_DATE_EPOCH nor git history is available")
+
+// SourceDate returns when the given .bib file was last updated, without
+// looking at the clock, so that building the site twice yields the same
+// bytes.  It's the time in the SOURCE_DATE_EPOCH environment variable, if
+// set, and the date of the last commit that changed the file otherwise.  An
+// invalid SOURCE_DATE_EPOCH is an error, not a reason to look elsewhere.  See
+// https://reproducible-builds.org/specs/source-date-epoch/
+func SourceDate(path string) (time.Time, error) {
+	if epoch, ok := os.LookupEnv("SOURCE_DATE_EPOCH"); ok {
+		seconds, err := strconv.ParseInt(strings.TrimSpace(epoch), 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q", epoch)
+		}
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+
+	dir, file := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	out, err := git(dir, "log", "--max-count=1", "--format=%cI", "--", file)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrNoSourceDate, err)
+	}
+	date := strings.TrimSpace(string(out))
+	if date == "" {
+		return time.Time{}, fmt.Errorf("%w: %s was never committed", ErrNoSourceDate, path)
+	}
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected output of git log: %w", err)
+	}
+	return t.UTC(), nil
+}
@@ -0,0 +1,70 @@
+package censorbib
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSourceDate(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	path := filepath.Join(t.TempDir(), "references.bib")
+	commitFile(t, path, "", "2024-03-14T23:30:00-02:00")
+
+	got, err := SourceDate(path)
+	if err != nil {
+		t.Fatalf("failed to get source date: %v", err)
+	}
+	if want := time.Date(2024, 3, 15, 1, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("expected %v but got %v", want, got)
+	}
+
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	got, err = SourceDate(path)
+	if err != nil {
+		t.Fatalf("failed to get source date: %v", err)
+	}
+	if want := time.Unix(1700000000, 0); !got.Equal(want) {
+		t.Errorf("expected %v but got %v", want, got)
+	}
+
+	t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	if _, err := SourceDate(path); err == nil || errors.Is(err, ErrNoSourceDate) {
+		t.Errorf("expected invalid SOURCE_DATE_EPOCH to be an error of its own, got %v", err)
+	}
+
+	// Without either, there is no source date.
+	os.Unsetenv("SOURCE_DATE_EPOCH")
+	if _, err := SourceDate(filepath.Join(t.TempDir(), "references.bib")); !errors.Is(err, ErrNoSourceDate) {
+		t.Errorf("expected ErrNoSourceDate, got %v", err)
+	}
+}
+
+func TestReproducibleHTML(t *testing.T) {
+	write := func() string {
+		bib, err := Load(strings.NewReader(feedBib))
+		if err != nil {
+			t.Fatalf("failed to load bibliography: %v", err)
+		}
+		bib.SetUpdated(time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC))
+		buf := new(bytes.Buffer)
+		if err := WriteHTML(buf, bib); err != nil {
+			t.Fatalf("failed to write HTML: %v", err)
+		}
+		return buf.String()
+	}
+	first := write()
+	if !strings.Contains(first, "Updated: 2024-03-14") {
+		t.Error("HTML is missing date of last update")
+	}
+	if second := write(); first != second {
+		t.Error("building the same bibliography twice yielded different HTML")
+	}
+}
@@ -1,163 +1,157 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"io"
 	"log"
 	"os"
-	"regexp"
-	"strings"
+	"path/filepath"
+	"time"
 
-	"github.com/nickng/bibtex"
+	"censorbib-go/censorbib"
 )
 
-// Matches e.g.: @inproceedings{Müller2024a,
-var re = regexp.MustCompile(`(?i)^@[a-z]+\s*\{\s*([^,\s]+)\s*,`)
-
-// Augment bibtex.BibEntry with the entry's raw record in the .bib file.
-type bibEntry struct {
-	bibtex.BibEntry
-	rawBibtex string
-}
-
-type searchEntry struct {
-	CiteName  string `json:"citeName"`
-	Title     string `json:"title"`
-	Authors   string `json:"authors"`
-	Venue     string `json:"venue"`
-	Year      string `json:"year"`
-	Publisher string `json:"publisher"`
-	RawBibtex string `json:"rawBibtex"`
-}
-
-func toStr(b bibtex.BibString) string {
-	if b == nil {
-		return ""
+func runLint(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	path := flags.String("path", "", "Path to .bib file.")
+	_ = flags.Parse(args)
+	if *path == "" {
+		log.Fatal("No path to .bib file provided.")
 	}
-	return b.String()
-}
 
-func parseBibFile(path string) []bibEntry {
-	contents, err := os.ReadFile(path)
+	contents, err := os.ReadFile(*path)
 	if err != nil {
 		log.Fatal(err)
 	}
-	file := bytes.NewReader(contents)
-	bib, err := bibtex.Parse(file)
-	if err != nil {
-		log.Fatal(err)
+	errs := censorbib.Lint(*path, contents)
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, err)
 	}
-
-	rawByCiteName := extractRawBibEntries(contents)
-	bibEntries := []bibEntry{}
-	for _, entry := range bib.Entries {
-		rawBibtex, ok := rawByCiteName[entry.CiteName]
-		if !ok {
-			log.Fatalf("could not find raw BibTeX for cite name: %s", entry.CiteName)
-		}
-		bibEntries = append(bibEntries, bibEntry{
-			BibEntry:  *entry,
-			rawBibtex: rawBibtex,
-		})
+	if len(errs) > 0 {
+		log.Fatalf("Found %d problem(s) in %s.", len(errs), *path)
 	}
-
-	return bibEntries
+	log.Printf("No problems found in %s.", *path)
 }
 
-func extractRawBibEntries(contents []byte) map[string]string {
-	rawByCiteName := make(map[string]string)
-	for i := 0; i < len(contents); i++ {
-		if contents[i] != '@' {
-			continue
-		}
-
-		depth := 0
-		for j := i; j < len(contents); j++ {
-			switch contents[j] {
-			case '{':
-				depth++
-			case '}':
-				depth--
-				if depth == 0 {
-					raw := strings.TrimSpace(string(contents[i : j+1]))
-					rawByCiteName[parseCiteName(raw)] = raw
-					i = j
-					goto nextEntry
-				}
-			}
-		}
-	nextEntry:
+func runFmt(args []string) {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	path := flags.String("path", "", "Path to .bib file.")
+	check := flags.Bool("check", false, "Don't rewrite the file; print a diff and fail if it isn't formatted.")
+	_ = flags.Parse(args)
+	if *path == "" {
+		log.Fatal("No path to .bib file provided.")
 	}
-	return rawByCiteName
-}
 
-func parseCiteName(line string) string {
-	matches := re.FindStringSubmatch(line)
-	if len(matches) != 2 {
-		log.Fatalf("failed to extract cite name of: %s", line)
+	contents, err := os.ReadFile(*path)
+	if err != nil {
+		log.Fatal(err)
 	}
-	return matches[1]
-}
-
-func mustFprint(w io.Writer, a ...any) {
-	if _, err := fmt.Fprint(w, a...); err != nil {
-		log.Fatalf("failed to write HTML: %v", err)
+	if *check {
+		diff, err := censorbib.FormatDiff(*path, contents)
+		if err != nil {
+			log.Fatalf("Failed to format: %v", err)
+		}
+		if diff != "" {
+			fmt.Print(diff)
+			log.Fatalf("%s is not formatted.  Run the fmt command without -check to fix.", *path)
+		}
+		return
 	}
-}
 
-func mustFprintln(w io.Writer, a ...any) {
-	if _, err := fmt.Fprintln(w, a...); err != nil {
-		log.Fatalf("failed to write HTML: %v", err)
+	formatted, err := censorbib.Format(*path, contents)
+	if err != nil {
+		log.Fatalf("Failed to format: %v", err)
 	}
-}
-
-func mustFprintf(w io.Writer, format string, a ...any) {
-	if _, err := fmt.Fprintf(w, format, a...); err != nil {
-		log.Fatalf("failed to write HTML: %v", err)
+	if string(formatted) == string(contents) {
+		return
+	}
+	if err := os.WriteFile(*path, formatted, 0o644); err != nil {
+		log.Fatal(err)
 	}
 }
 
-func run(w io.Writer, bibEntries []bibEntry) {
-	sortBibEntries(bibEntries)
-	mustFprint(w, header())
-	makeSearchBox(w, len(bibEntries))
-	mustFprintln(w, "<div id='container'>")
-	makeBib(w, bibEntries)
-	mustFprintln(w, "</div>")
-	makeReferenceDataScript(w, bibEntries)
-	mustFprint(w, footer())
-}
-
-func makeReferenceDataScript(w io.Writer, bibEntries []bibEntry) {
-	searchEntries := []searchEntry{}
-	for _, entry := range bibEntries {
-		searchEntries = append(searchEntries, searchEntry{
-			CiteName:  entry.CiteName,
-			Title:     entryTitle(&entry),
-			Authors:   entryAuthors(&entry),
-			Venue:     entryVenue(&entry),
-			Year:      toStr(entry.Fields["year"]),
-			Publisher: toStr(entry.Fields["publisher"]),
-			RawBibtex: entry.rawBibtex,
-		})
+// updatedDate returns the date of the last update that the page shows.  We
+// don't use the current date unless we have to, so that builds are
+// reproducible.
+func updatedDate(date, path string) (time.Time, error) {
+	if date != "" {
+		t, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid -date %q; expected YYYY-MM-DD", date)
+		}
+		return t, nil
 	}
-
-	mustFprintln(w, `<script id="reference-data" type="application/json">`)
-	if err := json.NewEncoder(w).Encode(searchEntries); err != nil {
-		log.Fatalf("failed to encode search data: %v", err)
+	t, err := censorbib.SourceDate(path)
+	if errors.Is(err, censorbib.ErrNoSourceDate) {
+		log.Printf("Showing today as the date of the last update, so the build isn't reproducible: %v", err)
+		return time.Now(), nil
 	}
-	mustFprintln(w, `</script>`)
+	return t, err
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "lint":
+			runLint(os.Args[2:])
+			return
+		case "fmt":
+			runFmt(os.Args[2:])
+			return
+		}
+	}
+
 	path := flag.String("path", "", "Path to .bib file.")
+	out := flag.String("out", "", "Write the static site into this directory instead of writing the HTML page to stdout.")
+	assets := flag.String("assets", "", "Path to the assets directory that -out copies.  Defaults to the assets directory next to the .bib file.")
+	inlineCrossrefs := flag.Bool("inline-crossrefs", false, "Copy inherited crossref fields into the raw BibTeX that the BibTeX modal shows.")
+	expandMacros := flag.Bool("expand-macros", false, "Expand @string macros in the raw BibTeX that the BibTeX modal shows.")
+	coins := flag.Bool("coins", false, "Embed COinS metadata in every entry, so that reference managers like Zotero can import papers from the page.")
+	gitAdded := flag.Bool("git-added", false, "Take the date that papers without an added field were added from the git history of the .bib file.")
+	date := flag.String("date", "", "Date of the last update that the page shows, as YYYY-MM-DD.  Defaults to SOURCE_DATE_EPOCH, or the date of the last commit that changed the .bib file.")
+	newDays := flag.Int("new-days", 30, "Mark papers that were added this many days before the last update as new.  0 marks none.")
 	flag.Parse()
 	if *path == "" {
 		log.Fatal("No path to .bib file provided.")
 	}
-	run(os.Stdout, parseBibFile(*path))
-	log.Println("Successfully created bibliography.")
+	bib, err := censorbib.LoadFile(*path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	updated, err := updatedDate(*date, *path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	bib.SetUpdated(updated)
+	bib.SetNewDays(*newDays)
+	if *gitAdded {
+		if err := bib.LoadAddedFromGit(*path); err != nil {
+			log.Fatalf("Failed to determine when papers were added: %v", err)
+		}
+	}
+	if *inlineCrossrefs {
+		bib.InlineCrossrefs()
+	}
+	if *expandMacros {
+		bib.ExpandMacros()
+	}
+	if *coins {
+		bib.EmbedCOinS()
+	}
+	if *out == "" {
+		if err := censorbib.WriteHTML(os.Stdout, bib); err != nil {
+			log.Fatalf("Failed to create bibliography: %v", err)
+		}
+		log.Println("Successfully created bibliography.")
+		return
+	}
+
+	if *assets == "" {
+		*assets = filepath.Join(filepath.Dir(*path), "assets")
+	}
+	if err := censorbib.WriteSite(*out, bib, *assets); err != nil {
+		log.Fatalf("Failed to create site: %v", err)
+	}
+	log.Printf("Successfully created site in %s.", *out)
 }